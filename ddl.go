@@ -0,0 +1,207 @@
+package filtersql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// DDLOptions configures ConfigFromDDL.
+type DDLOptions struct {
+	// Config supplies everything but the tables' columns, which are
+	// appended to its Comparisons.
+	Config Config
+	// Columns, when set, limits the exposed columns to the ones named, as
+	// name or table.name.
+	Columns []string
+	// Exclude hides the columns named, as name or table.name.
+	Exclude []string
+	// Operators replaces the default operators of the columns named, as
+	// name or table.name.
+	Operators map[string][]Op
+	// Qualify qualifies every column with its table name.
+	Qualify bool
+}
+
+// ConfigFromDDL derives a Config from the columns of MySQL CREATE TABLE
+// statements, e.g.
+//
+//	CREATE TABLE users (id BIGINT UNSIGNED NOT NULL, status ENUM('open', 'closed'))
+//
+// Integer types give IntColumn, Int64Column or, for BIGINT UNSIGNED,
+// Uint64Column, with unsigned types rejecting negatives. TINYINT(1) and BOOL
+// give BoolColumn, DECIMAL(p,s) a DecimalColumn with that precision, CHAR and
+// VARCHAR(n) a StringColumn limited to n characters, and ENUM a StringColumn
+// limited to its values. DATE, DATETIME and TIMESTAMP give the matching time
+// columns. Columns of other types are skipped, unless named in Columns.
+//
+// Numeric and time columns default to comparisons, in, not in and between,
+// strings and enums to equality, in and not in, and booleans to equality.
+// Nullable columns also allow is null and is not null.
+func ConfigFromDDL(ddl string, opts DDLOptions) (Config, error) {
+	config := opts.Config
+
+	pieces, err := sqlparser.SplitStatementToPieces(ddl)
+	if err != nil {
+		return Config{}, err
+	}
+
+	exposed := map[string]bool{}
+	for _, piece := range pieces {
+		statement, err := sqlparser.Parse(piece)
+		if err != nil {
+			return Config{}, err
+		}
+
+		table, ok := statement.(*sqlparser.CreateTable)
+		if !ok || table.TableSpec == nil {
+			return Config{}, fmt.Errorf("unsupported statement: %s", sqlparser.String(statement))
+		}
+
+		tableName := table.Table.Name.String()
+		for _, definition := range table.TableSpec.Columns {
+			name := definition.Name.String()
+			keys := []string{name, tableName + "." + name}
+
+			if len(opts.Columns) > 0 && !lo.Some(opts.Columns, keys) || lo.Some(opts.Exclude, keys) {
+				continue
+			}
+
+			columnType, options, err := ddlColumn(definition.Type, keys, opts)
+			if err != nil {
+				return Config{}, fmt.Errorf("column %s: %w", name, err)
+			}
+			if columnType == "" {
+				continue
+			}
+
+			if opts.Qualify {
+				options = append(options, Qualifier(tableName))
+			}
+
+			column := columnTypes[columnType](name, options...)
+			config.Allow.Comparisons = append(config.Allow.Comparisons, column)
+			exposed[keys[0]], exposed[keys[1]] = true, true
+		}
+	}
+
+	for _, name := range opts.Columns {
+		if !exposed[name] {
+			return Config{}, fmt.Errorf("unsupported column: %s", name)
+		}
+	}
+
+	return config, nil
+}
+
+var (
+	numericOps = []Op{Eq, Ne, Gt, Lt, Gte, Lte, In, NotIn, Within}
+	stringOps  = []Op{Eq, Ne, In, NotIn}
+	boolOps    = []Op{Eq, Ne}
+)
+
+// ddlColumn returns the LoadConfig column type and options for a column
+// definition, or an empty type for unsupported column types.
+func ddlColumn(columnType *sqlparser.ColumnType, keys []string, opts DDLOptions) (string, []ColumnOption, error) {
+	kind, ops, options := "", numericOps, []ColumnOption{}
+
+	length, err := ddlLength(columnType.Length)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch strings.ToUpper(columnType.Type) {
+	case "BOOL", "BOOLEAN":
+		kind, ops = "bool", boolOps
+	case "TINYINT":
+		if length == 1 {
+			kind, ops = "bool", boolOps
+		} else {
+			kind = "int"
+		}
+	case "SMALLINT", "MEDIUMINT":
+		kind = "int"
+	case "INT", "INTEGER":
+		kind = lo.Ternary(columnType.Unsigned, "int64", "int")
+	case "BIGINT":
+		kind = lo.Ternary(columnType.Unsigned, "uint64", "int64")
+	case "FLOAT", "DOUBLE", "REAL":
+		kind = "float"
+	case "DECIMAL", "NUMERIC", "DEC":
+		scale, err := ddlLength(columnType.Scale)
+		if err != nil {
+			return "", nil, err
+		}
+		// MySQL defaults DECIMAL to DECIMAL(10, 0).
+		kind = "decimal"
+		options = append(options, Precision(lo.Ternary(length == UNLIMITED, 10, length), lo.Ternary(scale == UNLIMITED, 0, scale)))
+	case "CHAR", "VARCHAR":
+		kind, ops = "string", stringOps
+		options = append(options, MaxLength(length))
+	case "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		kind, ops = "string", stringOps
+	case "ENUM":
+		values := make([]string, 0, len(columnType.EnumValues))
+		for _, value := range columnType.EnumValues {
+			unquoted, err := ddlUnquote(value)
+			if err != nil {
+				return "", nil, err
+			}
+			values = append(values, unquoted)
+		}
+		kind, ops = "string", stringOps
+		options = append(options, Enum(values...))
+	case "DATE":
+		kind = "date"
+	case "DATETIME":
+		kind = "datetime"
+	case "TIMESTAMP":
+		kind = "timestamp"
+	default:
+		if len(opts.Columns) > 0 {
+			return "", nil, fmt.Errorf("unsupported column type: %s", columnType.Type)
+		}
+		return "", nil, nil
+	}
+
+	if columnType.Unsigned && kind != "bool" {
+		option, err := parseRange(kind, map[string]string{"min": "0"})
+		if err != nil {
+			return "", nil, err
+		}
+		options = append(options, option)
+	}
+
+	if override, ok := lo.Find(keys, func(key string) bool { return opts.Operators[key] != nil }); ok {
+		ops = opts.Operators[override]
+	} else if columnType.Options == nil || columnType.Options.Null == nil || *columnType.Options.Null {
+		ops = append(ops[:len(ops):len(ops)], Null, NotNull)
+	}
+
+	return kind, append(options, Operators(ops...)), nil
+}
+
+// ddlLength returns a column type's length or scale, or UNLIMITED if unset.
+func ddlLength(literal *sqlparser.Literal) (int, error) {
+	if literal == nil {
+		return UNLIMITED, nil
+	}
+	return strconv.Atoi(literal.Val)
+}
+
+// ddlUnquote unquotes an ENUM value, which the parser keeps as a quoted
+// and escaped string literal.
+func ddlUnquote(value string) (string, error) {
+	expr, err := sqlparser.ParseExpr(value)
+	if err != nil {
+		return "", err
+	}
+	literal, ok := expr.(*sqlparser.Literal)
+	if !ok || literal.Type != sqlparser.StrVal {
+		return "", fmt.Errorf("invalid enum value: %s", value)
+	}
+	return literal.Val, nil
+}
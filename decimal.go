@@ -0,0 +1,61 @@
+package filtersql
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal is an exact decimal number, Unscaled × 10^-Scale, as written in a
+// filter. 1.50 is {150, 2}.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+// ParseDecimal parses a plain decimal number such as -12.50, keeping its
+// scale as written.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	if len(digits) < len(s)-1 {
+		return Decimal{}, fmt.Errorf("invalid decimal: %s", s)
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	if whole+fraction == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal: %s", s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal: %s", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{Unscaled: unscaled, Scale: len(fraction)}, nil
+}
+
+// Precision is the number of digits needed to store the number in a SQL
+// DECIMAL column with the same scale.
+func (d Decimal) Precision() int {
+	digits := len(new(big.Int).Abs(d.Unscaled).String())
+	if d.Scale > digits {
+		return d.Scale
+	}
+	return digits
+}
+
+func (d Decimal) Rat() *big.Rat {
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Scale)), nil)
+	return new(big.Rat).SetFrac(d.Unscaled, denominator)
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	return d.Rat().Cmp(other.Rat())
+}
+
+func (d Decimal) String() string {
+	return d.Rat().FloatString(d.Scale)
+}
@@ -0,0 +1,118 @@
+package filtersql
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// Dialect selects the SQL flavour that Parse and ParseParams render. The
+// accepted filter grammar is always MySQL's, whatever the Dialect.
+type Dialect int
+
+const (
+	MySQL Dialect = iota
+	PostgreSQL
+	SQLite
+	SQLServer
+)
+
+func (dialect Dialect) String() string {
+	switch dialect {
+	case MySQL:
+		return "mysql"
+	case PostgreSQL:
+		return "postgresql"
+	case SQLite:
+		return "sqlite"
+	case SQLServer:
+		return "sqlserver"
+	default:
+		return fmt.Sprintf("dialect(%d)", int(dialect))
+	}
+}
+
+// placeholder returns the bind parameter marker for the nth (1-based) argument.
+func (dialect Dialect) placeholder(n int) string {
+	switch dialect {
+	case PostgreSQL:
+		return fmt.Sprintf("$%d", n)
+	case SQLServer:
+		return fmt.Sprintf("@p%d", n)
+	default:
+		return "?"
+	}
+}
+
+func (dialect Dialect) quoteIdentifier(name string) string {
+	switch dialect {
+	case PostgreSQL, SQLite:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	case SQLServer:
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	default:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+}
+
+func (dialect Dialect) quoteString(val string) string {
+	switch dialect {
+	case MySQL:
+		return sqlparser.String(sqlparser.NewStrLiteral(val))
+	case SQLServer:
+		// N marks the literal as Unicode, rather than the database's code page.
+		return "N'" + strings.ReplaceAll(val, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+	}
+}
+
+func (dialect Dialect) comparisonOperator(op sqlparser.ComparisonExprOperator) (string, error) {
+	if dialect == MySQL {
+		return op.ToString(), nil
+	}
+
+	switch op {
+	case sqlparser.NotEqualOp:
+		return "<>", nil
+	case sqlparser.NullSafeEqualOp:
+		if dialect == SQLite {
+			return "is", nil
+		}
+		return "is not distinct from", nil
+	case sqlparser.RegexpOp:
+		switch dialect {
+		case PostgreSQL:
+			return "~", nil
+		case SQLite:
+			return op.ToString(), nil
+		}
+	case sqlparser.NotRegexpOp:
+		switch dialect {
+		case PostgreSQL:
+			return "!~", nil
+		case SQLite:
+			return op.ToString(), nil
+		}
+	default:
+		return op.ToString(), nil
+	}
+
+	return "", fmt.Errorf("unsupported operator for %s: %s", dialect, op.ToString())
+}
+
+// needsLikeEscape reports whether a LIKE comparison needs an explicit ESCAPE
+// clause to keep MySQL's default \ escape character, which SQLite and SQL
+// Server lack.
+func (dialect Dialect) needsLikeEscape(node *sqlparser.ComparisonExpr) bool {
+	if dialect != SQLite && dialect != SQLServer {
+		return false
+	}
+	if node.Operator != sqlparser.LikeOp && node.Operator != sqlparser.NotLikeOp {
+		return false
+	}
+
+	pattern, ok := node.Right.(*sqlparser.Literal)
+	return ok && strings.Contains(pattern.Val, "\\")
+}
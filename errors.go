@@ -0,0 +1,70 @@
+package filtersql
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies why a filter was rejected.
+type ErrorCode int
+
+const (
+	SyntaxError ErrorCode = iota
+	UnknownColumn
+	UnsupportedOperator
+	InvalidValue
+	LimitExceeded
+)
+
+func (code ErrorCode) String() string {
+	switch code {
+	case SyntaxError:
+		return "syntax_error"
+	case UnknownColumn:
+		return "unknown_column"
+	case UnsupportedOperator:
+		return "unsupported_operator"
+	case InvalidValue:
+		return "invalid_value"
+	case LimitExceeded:
+		return "limit_exceeded"
+	default:
+		return fmt.Sprintf("error_code(%d)", int(code))
+	}
+}
+
+// FilterError is returned for every filter that is rejected. Mistakes in
+// the Config itself, such as an invalid Column.Target, are plain errors
+// instead, which Config.Validate reports up front. Column and Operator are
+// set when the offending expression has them. Offset is the
+// byte offset in the filter passed to Parse of the first column or value in
+// the offending expression, or -1 when the error has no single position.
+type FilterError struct {
+	Code     ErrorCode
+	Column   string
+	Operator string
+	Offset   int
+	Message  string
+}
+
+func (err *FilterError) Error() string {
+	return err.Message
+}
+
+// FilterErrors returns every *FilterError within err, which may be a single
+// *FilterError or, with Config.CollectErrors, several joined together.
+func FilterErrors(err error) []*FilterError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		result := []*FilterError{}
+		for _, err := range joined.Unwrap() {
+			result = append(result, FilterErrors(err)...)
+		}
+		return result
+	}
+
+	var filterErr *FilterError
+	if errors.As(err, &filterErr) {
+		return []*FilterError{filterErr}
+	}
+	return []*FilterError{}
+}
@@ -0,0 +1,122 @@
+package filtersql
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// CustomOperator allows any operator vitess parses as a comparison, such as
+// REGEXP or <=>, e.g.
+//
+//	CustomOperator{Operator: sqlparser.RegexpOp, RightsAccessor: Rights{String(Any[string]())}}
+//
+// ValidationFunc is optional. It is called once the RHS has matched one of
+// the rights, and an error rejects the comparison with the error's message.
+type CustomOperator struct {
+	Operator       sqlparser.ComparisonExprOperator
+	RightsAccessor Rights
+	ValidationFunc func(*sqlparser.ComparisonExpr) error
+}
+
+func (CustomOperator) iComparisonOperator() {}
+func (co CustomOperator) ToString() string  { return co.Operator.ToString() }
+func (co CustomOperator) Rights() Rights    { return co.RightsAccessor }
+func (co CustomOperator) checkComparison(node *sqlparser.ComparisonExpr) error {
+	if co.ValidationFunc == nil {
+		return nil
+	}
+	return co.ValidationFunc(node)
+}
+
+// ValueType is implemented by value types defined outside the package, such
+// as enum-backed Go types or hex literals. Validate receives the RHS, from
+// or to expression as parsed, once it is known to be a literal, a boolean or
+// a tuple of those, which DecodeValue turns into a Go value, and
+// an error rejects it with the error's message. Wrap one in CustomValue to
+// use it in any operator's rights, froms or tos.
+type ValueType interface {
+	Validate(sqlparser.Expr) error
+}
+
+// CustomValue adapts a ValueType for use as a right, from or to.
+type CustomValue struct {
+	ValueType ValueType
+}
+
+func (CustomValue) iEqualsOperatorRight()             {}
+func (CustomValue) iNotEqualsOperatorRight()          {}
+func (CustomValue) iGreaterThanOperatorRight()        {}
+func (CustomValue) iLessThanOperatorRight()           {}
+func (CustomValue) iGreaterThanOrEqualOperatorRight() {}
+func (CustomValue) iLessThanOrEqualOperatorRight()    {}
+func (CustomValue) iInOperatorRight()                 {}
+func (CustomValue) iNotInOperatorRight()              {}
+func (CustomValue) iLikeOperatorRight()               {}
+func (CustomValue) iNotLikeOperatorRight()            {}
+func (CustomValue) iBetweenOperatorFrom()             {}
+func (CustomValue) iBetweenOperatorTo()               {}
+func (CustomValue) iNotBetweenOperatorFrom()          {}
+func (CustomValue) iNotBetweenOperatorTo()            {}
+func (CustomValue) iRight()                           {}
+func (cv CustomValue) Right() Right                   { return cv }
+func (CustomValue) iFrom()                            {}
+func (cv CustomValue) From() From                     { return cv }
+func (CustomValue) iTo()                              {}
+func (cv CustomValue) To() To                         { return cv }
+func (cv CustomValue) valid(e any) bool               { return cv.checkValue(e) == nil }
+func (CustomValue) nodeType() string                  { return "" }
+func (CustomValue) matchesNodeType(string) bool       { return true }
+func (cv CustomValue) checkValue(e any) error {
+	expr, ok := e.(sqlparser.Expr)
+	if !ok {
+		return fmt.Errorf("unsupported value: %v", e)
+	}
+	// Only values reach the ValueType, so that one which accepts anything
+	// can't let a subquery or function call through.
+	if !plainValue(expr) {
+		return fmt.Errorf("unsupported value: %s", sqlparser.String(expr))
+	}
+	return cv.ValueType.Validate(expr)
+}
+
+// plainValue reports whether an expression is a literal, a boolean or a
+// tuple of those.
+func plainValue(expr sqlparser.Expr) bool {
+	switch node := expr.(type) {
+	case *sqlparser.Literal, sqlparser.BoolVal:
+		return true
+	case sqlparser.ValTuple:
+		return lo.EveryBy(node, func(item sqlparser.Expr) bool {
+			_, tuple := item.(sqlparser.ValTuple)
+			return !tuple && plainValue(item)
+		})
+	default:
+		return false
+	}
+}
+
+// DecodeValue returns the Go value of a literal, boolean or tuple, in the
+// same form as the args of ParseParams: int64, float64, string, []byte or
+// bool, and []any for a tuple. Integers above math.MaxInt64 are strings.
+func DecodeValue(expr sqlparser.Expr) (any, error) {
+	switch node := expr.(type) {
+	case *sqlparser.Literal:
+		return literalArg(node), nil
+	case sqlparser.BoolVal:
+		return bool(node), nil
+	case sqlparser.ValTuple:
+		values := make([]any, 0, len(node))
+		for _, item := range node {
+			value, err := DecodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported value: %s", sqlparser.String(expr))
+	}
+}
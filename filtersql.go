@@ -3,7 +3,6 @@ package filtersql
 import (
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
@@ -14,139 +13,218 @@ import (
 const UNLIMITED = -1
 
 func (config Config) Parse(filter string) (string, error) {
+	where, err := config.parse(filter)
+	if err != nil || where == nil {
+		return "", err
+	}
+
+	expr, err := config.mapColumns(where.Expr)
+	if err != nil {
+		return "", err
+	}
+
+	return render(expr, config.Dialect)
+}
+
+// ParseParams validates the filter in the same way as Parse, but returns it
+// with every literal replaced by a placeholder in the style of the
+// configured Dialect, along with the typed arguments to bind to those
+// placeholders, in order.
+func (config Config) ParseParams(filter string) (string, []any, error) {
+	where, err := config.parse(filter)
+	if err != nil || where == nil {
+		return "", nil, err
+	}
+
+	expr, err := config.mapColumns(where.Expr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return renderParams(expr, config.Dialect)
+}
+
+func (config Config) parse(filter string) (*sqlparser.Where, error) {
 	if strings.Trim(filter, " ") == "" {
-		return "", nil
+		return nil, nil
 	}
 
-	sqlBlob := fmt.Sprintf("SELECT * FROM `not_a_table` WHERE %s", filter)
+	prefix := "SELECT * FROM `not_a_table` WHERE "
+	sqlBlob := prefix + filter
 	sql, remainder, err := sqlparser.SplitStatement(sqlBlob)
 	if err != nil {
-		return "", err
+		return nil, &FilterError{Code: SyntaxError, Offset: -1, Message: err.Error()}
 	}
 	if remainder != "" {
-		return "", errors.New("unsupported syntax")
+		return nil, &FilterError{Code: SyntaxError, Offset: len(sql) - len(prefix), Message: "unsupported syntax"}
 	}
 
 	stmt, err := sqlparser.Parse(sql)
 	if err != nil {
-		return "", errors.New("unsupported syntax")
+		return nil, &FilterError{Code: SyntaxError, Offset: syntaxErrorOffset(filter, sql, len(prefix)), Message: "unsupported syntax"}
 	}
 
 	where := stmt.(*sqlparser.Select).Where
-
-	if err = config.validateGroupingParens(sql); err != nil {
-		return "", err
+	normalizeSignedLiterals(where)
+
+	offsets := newOffsets(filter, where.Expr)
+	errs := []error{}
+	validations := []func() error{
+		func() error { return config.validateGroupingParens(filter, where) },
+		func() error { return config.validateDepth(where, offsets) },
+		func() error { return config.validateAST(where, offsets) },
 	}
 
-	if err = config.validateAnds(sql); err != nil {
-		return "", err
+	for _, validation := range validations {
+		if err = validation(); err != nil {
+			if !config.CollectErrors {
+				return nil, err
+			}
+			errs = append(errs, err)
+		}
 	}
 
-	if err = config.validateOrs(sql); err != nil {
-		return "", err
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	if err = config.validateNots(sql); err != nil {
-		return "", err
-	}
+	return where, nil
+}
 
-	if err = config.validateAST(where); err != nil {
-		return "", err
+func (config Config) validateAST(filter *sqlparser.Where, offsets offsets) error {
+	errs := []error{}
+	counts := limits{}
+	fail := func(err *FilterError, node sqlparser.SQLNode) (bool, error) {
+		if !config.CollectErrors {
+			return false, err
+		}
+
+		// Keep walking, into the children of boolean expressions too, as
+		// they may hold further problems of their own.
+		errs = append(errs, err)
+		switch node.(type) {
+		case *sqlparser.AndExpr, *sqlparser.OrExpr, *sqlparser.NotExpr:
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
+	walkError := func(code ErrorCode, message string, node sqlparser.SQLNode) (bool, error) {
+		return fail(config.nodeError(code, fmt.Sprintf(message, sqlparser.String(node)), node, offsets), node)
+	}
+	walkLimitError := func(message string, node sqlparser.SQLNode) (bool, error) {
+		return fail(config.nodeError(LimitExceeded, message, node, offsets), node)
 	}
 
-	result := sqlparser.String(where)
-	result = strings.Replace(result, " where ", "", 1)
-	return result, nil
-}
+	countTuple := func(node sqlparser.ValTuple) (bool, error) {
+		counts.tuples++
+		if withinCap(counts.tuples, config.Allow.TupleParens) {
+			return true, nil
+		} else {
+			return walkLimitError("unsupported tuple parens", node)
+		}
+	}
 
-func (config Config) validateAST(filter *sqlparser.Where) error {
 	fun := func(node sqlparser.SQLNode) (bool, error) {
 		switch node := node.(type) {
-		case *sqlparser.Where, *sqlparser.Literal, sqlparser.ValTuple:
+		case *sqlparser.Where:
 			return true, nil
 		case *sqlparser.AndExpr:
-			max := config.Allow.Ands
-			if max == UNLIMITED || max > 0 {
+			counts.ands++
+			if withinLimit(counts.ands, config.Allow.Ands) {
 				return true, nil
 			} else {
-				return config.walkError("unsupported and: %s", node)
+				return walkLimitError("unsupported and", node)
 			}
 		case *sqlparser.OrExpr:
-			max := config.Allow.Ors
-			if max == UNLIMITED || max > 0 {
+			counts.ors++
+			if withinLimit(counts.ors, config.Allow.Ors) {
 				return true, nil
 			} else {
-				return config.walkError("unsupported or: %s", node)
+				return walkLimitError("unsupported or", node)
 			}
 		case *sqlparser.NotExpr:
-			max := config.Allow.Nots
-			if max == UNLIMITED || max > 0 {
-				return true, nil
-			} else {
-				return config.walkError("unsupported not: %s", node)
-			}
-		case *sqlparser.ColName:
-			result := lo.ContainsBy(config.allowedLeftColumns(), func(item Column) bool { return node.Name.EqualString(item.Name) })
-
-			if result {
-				return true, nil
-			} else {
-				return config.walkError("unsupported column name: %s", node.Name)
-			}
-		case sqlparser.IdentifierCI:
-			result := lo.ContainsBy(config.allowedLeftColumns(), func(item Column) bool { return node.EqualString(item.Name) })
-
-			if result {
+			counts.nots++
+			if withinLimit(counts.nots, config.Allow.Nots) {
 				return true, nil
 			} else {
-				return config.walkError("unsupported column name: %s", node)
+				return walkLimitError("unsupported not", node)
 			}
-		case sqlparser.IdentifierCS:
-			result := lo.ContainsBy(config.allowedLeftColumns(), func(item Column) bool { return node.String() == item.Qualifier })
-
-			if result {
-				return true, nil
-			} else {
-				return config.walkError("unsupported table name: %s", node)
+		case *sqlparser.BetweenExpr:
+			counts.predicates++
+			if !withinCap(counts.predicates, config.Allow.MaxPredicates) {
+				return walkLimitError("unsupported predicates", node)
 			}
-		case sqlparser.TableName:
-			result := lo.ContainsBy(config.allowedLeftColumns(), func(item Column) bool { return node.Name.String() == item.Qualifier })
 
-			if result {
-				return true, nil
-			} else {
-				return config.walkError("unsupported table name: %s", node)
-			}
-		case *sqlparser.BetweenExpr:
 			switch lhs := node.Left.(type) {
 			case *sqlparser.ColName:
 				if columnConfig, found := config.findColumn(lhs); found {
-					if columnConfig.BetweenOperator == nil {
-						return config.walkError("unsupported operator: %s", node)
+					bop, bopFound := columnConfig.betweenOperator(node.IsBetween)
+					if !bopFound {
+						return walkError(UnsupportedOperator, "unsupported operator: %s", node)
 					}
 
-					from, fromFound := lo.Find(columnConfig.BetweenOperator.Froms(), func(from From) bool {
-						fromNodeType := fmt.Sprintf("%T", node.From)
-						return fromNodeType == from.nodeType()
+					from, fromValid := lo.Find(bop.Froms(), func(from From) bool {
+						return matchesNodeType(from, node.From) && from.valid(node.From)
 					})
 
-					to, toFound := lo.Find(columnConfig.BetweenOperator.Tos(), func(to To) bool {
-						toNodeType := fmt.Sprintf("%T", node.To)
-						return toNodeType == to.nodeType()
+					to, toValid := lo.Find(bop.Tos(), func(to To) bool {
+						return matchesNodeType(to, node.To) && to.valid(node.To)
 					})
 
-					if (fromFound && toFound) && (from.valid(node.From) && to.valid(node.To)) {
-						return true, nil
+					if fromValid && toValid {
+						if validator, ok := bop.(betweenValidator); ok && !validator.validBetween(node) {
+							return walkError(InvalidValue, "unsupported range: %s", node)
+						}
+
+						// The values are checked, so the walk stops here, where
+						// a boolean value can't pass for a bare true or false.
+						node.From = canonical(from, node.From)
+						node.To = canonical(to, node.To)
+						return false, nil
+					} else if valuesOutOfRange(bop.Froms(), node.From) || valuesOutOfRange(bop.Tos(), node.To) {
+						return walkError(InvalidValue, "out of range value: %s", node)
+					} else if err := valuesError(bop.Froms(), node.From, fromValid); err != nil {
+						return fail(config.nodeError(InvalidValue, err.Error(), node, offsets), node)
+					} else if err := valuesError(bop.Tos(), node.To, toValid); err != nil {
+						return fail(config.nodeError(InvalidValue, err.Error(), node, offsets), node)
 					} else {
-						return config.walkError("unsupported or invalid RHS: %s", node)
+						return walkError(InvalidValue, "unsupported or invalid RHS: %s", node)
 					}
 				} else {
-					return config.walkError("unsupported operator: %s", node)
+					return walkError(UnknownColumn, "unsupported operator: %s", node)
+				}
+			}
+
+			return walkError(SyntaxError, "unsupported between: %s", node)
+		case *sqlparser.IsExpr:
+			counts.predicates++
+			if !withinCap(counts.predicates, config.Allow.MaxPredicates) {
+				return walkLimitError("unsupported predicates", node)
+			}
+
+			switch lhs := node.Left.(type) {
+			case *sqlparser.ColName:
+				if columnConfig, found := config.findColumn(lhs); found {
+					if lo.ContainsBy(columnConfig.IsOperators, func(op IIsOperator) bool {
+						return node.Right.ToString() == op.ToString()
+					}) {
+						return false, nil
+					} else {
+						return walkError(UnsupportedOperator, "unsupported operator: %s", node)
+					}
 				}
+
+				return walkError(UnknownColumn, "unsupported comparison: %s", node)
 			}
 
-			return config.walkError("unsupported between: %s", node)
+			return walkError(SyntaxError, "unsupported is: %s", node)
 		case *sqlparser.ComparisonExpr:
+			counts.predicates++
+			if !withinCap(counts.predicates, config.Allow.MaxPredicates) {
+				return walkLimitError("unsupported predicates", node)
+			}
+
 			switch lhs := node.Left.(type) {
 			case *sqlparser.ColName:
 				if columnConfig, found := config.findColumn(lhs); found {
@@ -155,26 +233,56 @@ func (config Config) validateAST(filter *sqlparser.Where) error {
 					})
 
 					if copFound {
-						right, rightFound := lo.Find(cop.Rights(), func(right Right) bool {
-							rightNodeType := fmt.Sprintf("%T", node.Right)
-							return rightNodeType == right.nodeType()
+						if _, err := config.Dialect.comparisonOperator(node.Operator); err != nil {
+							return fail(config.nodeError(UnsupportedOperator, err.Error(), node, offsets), node)
+						}
+
+						right, rightValid := lo.Find(cop.Rights(), func(right Right) bool {
+							return matchesNodeType(right, node.Right) && right.valid(node.Right)
 						})
 
-						if rightFound && right.valid(node.Right) {
-							return true, nil
-						} else {
-							return config.walkError("unsupported or invalid RHS: %s", node)
+						if !rightValid && valuesOutOfRange(cop.Rights(), node.Right) {
+							return walkError(InvalidValue, "out of range value: %s", node)
 						}
+
+						if err := valuesError(cop.Rights(), node.Right, rightValid); err != nil {
+							return fail(config.nodeError(InvalidValue, err.Error(), node, offsets), node)
+						}
+
+						if !rightValid {
+							return walkError(InvalidValue, "unsupported or invalid RHS: %s", node)
+						}
+
+						if validator, ok := cop.(comparisonValidator); ok && !validator.validComparison(node) {
+							return walkError(InvalidValue, "unsupported pattern: %s", node)
+						}
+
+						if checker, ok := cop.(comparisonChecker); ok {
+							if err := checker.checkComparison(node); err != nil {
+								return fail(config.nodeError(InvalidValue, err.Error(), node, offsets), node)
+							}
+						}
+
+						// The value is checked, so the walk stops here, where a
+						// boolean value can't pass for a bare true or false.
+						node.Right = canonical(right, node.Right)
+						if tuple, ok := node.Right.(sqlparser.ValTuple); ok {
+							_, err := countTuple(tuple)
+							return false, err
+						}
+						return false, nil
 					} else {
-						return config.walkError("unsupported operator: %s", node)
+						return walkError(UnsupportedOperator, "unsupported operator: %s", node)
 					}
 				}
+
+				return walkError(UnknownColumn, "unsupported comparison: %s", node)
 			}
 
-			return config.walkError("unsupported comparison: %s", node)
+			return walkError(SyntaxError, "unsupported comparison: %s", node)
 		default:
 			{
-				return config.walkError("unsupported syntax: %s", node)
+				return walkError(SyntaxError, "unsupported syntax: %s", node)
 			}
 		}
 	}
@@ -183,91 +291,197 @@ func (config Config) validateAST(filter *sqlparser.Where) error {
 	if err != nil {
 		return err
 	} else {
+		return errors.Join(errs...)
+	}
+}
+
+// valuesError returns the first error of the values defined outside the
+// package that reject the expression, unless another value accepted it.
+func valuesError[T any](values []T, expr sqlparser.Expr, accepted bool) error {
+	if accepted {
 		return nil
 	}
+
+	for _, value := range values {
+		if checker, ok := any(value).(valueChecker); ok {
+			if err := checker.checkValue(expr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
-func (config Config) findColumn(lhs *sqlparser.ColName) (Column, bool) {
-	return lo.Find(config.allowedLeftColumns(), func(col Column) bool {
-		return lhs.Qualifier.Name.String() == col.Qualifier && lhs.Name.EqualString(col.Name)
-	})
+// betweenOperator returns the column's between or not between operator.
+func (col Column) betweenOperator(isBetween bool) (interface {
+	Froms() Froms
+	Tos() Tos
+}, bool) {
+	if isBetween {
+		return col.BetweenOperator, col.BetweenOperator != nil
+	}
+	return col.NotBetweenOperator, col.NotBetweenOperator != nil
 }
 
-func (config Config) allowedLeftColumns() []Column {
-	return lo.FilterMap(config.Allow.Comparisons, func(item ILeft, index int) (Column, bool) {
-		a, b := item.(Column)
-		return a, b
+// valuesOutOfRange reports whether any of the values of the expression's kind
+// rejects it for not fitting, rather than for failing validation.
+func valuesOutOfRange[T interface{ nodeType() string }](values []T, expr sqlparser.Expr) bool {
+	return lo.ContainsBy(values, func(value T) bool {
+		return matchesNodeType(value, expr) && isOutOfRange(value, expr)
 	})
 }
 
-func (config Config) walkError(message string, node sqlparser.SQLNode) (bool, error) {
-	if config.Debug {
-		spew.Dump(node)
-	}
-	return false, fmt.Errorf(message, sqlparser.String(node))
+func (config Config) findColumn(lhs *sqlparser.ColName) (Column, bool) {
+	return lo.Find(config.allowedLeftColumns(), func(col Column) bool {
+		return lhs.Qualifier.Qualifier.IsEmpty() && lhs.Qualifier.Name.String() == col.Qualifier && lhs.Name.EqualString(col.Name)
+	})
 }
 
-func (config Config) validateGroupingParens(query string) error {
-	noStringValues := noStringValues(query)
-
-	leftParens := strings.Count(noStringValues, "(")
-	rightParents := strings.Count(noStringValues, ")")
+// normalizeSignedLiterals folds a unary minus into the numeric literal it
+// negates, as vitess parses -5 as a *sqlparser.UnaryExpr. This lets signed
+// numbers be validated like any other literal. Other unary operators, and
+// repeated minuses, are left in place to be rejected.
+func normalizeSignedLiterals(where *sqlparser.Where) {
+	sqlparser.Rewrite(where, func(cursor *sqlparser.Cursor) bool {
+		unary, ok := cursor.Node().(*sqlparser.UnaryExpr)
+		if !ok || unary.Operator != sqlparser.UMinusOp {
+			return true
+		}
 
-	max := config.Allow.GroupingParens
+		literal, ok := unary.Expr.(*sqlparser.Literal)
+		if !ok || !lo.Contains([]sqlparser.ValType{sqlparser.IntVal, sqlparser.FloatVal, sqlparser.DecimalVal}, literal.Type) {
+			return true
+		}
 
-	if leftParens == rightParents && (max == UNLIMITED || leftParens <= max) {
-		return nil
-	} else {
-		return fmt.Errorf("unsupported parens")
-	}
+		cursor.Replace(&sqlparser.Literal{Type: literal.Type, Val: "-" + literal.Val})
+		return false
+	}, nil)
 }
 
-func (config Config) validateAnds(query string) error {
-	noStringValues := noStringValues(query)
+// mapColumns replaces every column that declares a Target with the target
+// expression, and every other column with its configured name, leaving the
+// expression otherwise untouched.
+func (config Config) mapColumns(expr sqlparser.Expr) (sqlparser.Expr, error) {
+	var err error
 
-	lowercaseQuery := strings.ToLower(noStringValues)
-	andsCount := strings.Count(lowercaseQuery, " and ")
+	result := sqlparser.Rewrite(expr, func(cursor *sqlparser.Cursor) bool {
+		lhs, ok := cursor.Node().(*sqlparser.ColName)
+		if !ok {
+			return true
+		}
 
-	max := config.Allow.Ands
+		column, found := config.findColumn(lhs)
+		if !found {
+			return true
+		}
 
-	if max == UNLIMITED || andsCount <= max {
-		return nil
-	} else {
-		return fmt.Errorf("unsupported and")
+		// Columns match case-insensitively, so render the configured name
+		// rather than the filter's spelling of it.
+		if column.Target == "" {
+			cursor.Replace(&sqlparser.ColName{Name: sqlparser.NewIdentifierCI(column.Name), Qualifier: lhs.Qualifier})
+			return false
+		}
+
+		target, targetErr := column.target()
+		if targetErr != nil {
+			err = targetErr
+			return false
+		}
+
+		cursor.Replace(target)
+		return false
+	}, nil)
+
+	if err != nil {
+		return nil, err
 	}
+	return result.(sqlparser.Expr), nil
 }
 
-func (config Config) validateOrs(query string) error {
-	noStringValues := noStringValues(query)
+func (column Column) target() (sqlparser.Expr, error) {
+	target, err := sqlparser.ParseExpr(column.Target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid column target: %s", column.Target)
+	}
 
-	lowercaseQuery := strings.ToLower(noStringValues)
-	orsCount := strings.Count(lowercaseQuery, " or ")
+	// Comparisons render without parens, so a boolean target such as a and b
+	// would take the comparison into its right-hand side.
+	switch target.(type) {
+	case *sqlparser.AndExpr, *sqlparser.OrExpr, *sqlparser.XorExpr, *sqlparser.NotExpr,
+		*sqlparser.ComparisonExpr, *sqlparser.IsExpr, *sqlparser.BetweenExpr:
+		return nil, fmt.Errorf("invalid column target: %s", column.Target)
+	}
 
-	max := config.Allow.Ors
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node.(type) {
+		case *sqlparser.Subquery, sqlparser.Argument, *sqlparser.Variable:
+			return false, fmt.Errorf("invalid column target: %s", column.Target)
+		default:
+			return true, nil
+		}
+	}, target)
 
-	if max == UNLIMITED || orsCount <= max {
-		return nil
-	} else {
-		return fmt.Errorf("unsupported or")
-	}
+	return target, err
 }
 
-func (config Config) validateNots(query string) error {
-	noStringValues := noStringValues(query)
+func (config Config) allowedLeftColumns() []Column {
+	return lo.FilterMap(config.Allow.Comparisons, func(item ILeft, index int) (Column, bool) {
+		switch item := item.(type) {
+		case Column:
+			return item, true
+		case TypedColumn:
+			return item.Column(), true
+		default:
+			return Column{}, false
+		}
+	})
+}
 
-	lowercaseQuery := strings.ToLower(noStringValues)
-	notsCount := strings.Count(lowercaseQuery, " not ")
+func (config Config) nodeError(code ErrorCode, message string, node sqlparser.SQLNode, offsets offsets) *FilterError {
+	if config.Debug {
+		spew.Dump(node)
+	}
 
-	max := config.Allow.Nots
+	err := &FilterError{
+		Code:    code,
+		Offset:  offsets.of(node),
+		Message: message,
+	}
 
-	if max == UNLIMITED || notsCount <= max {
-		return nil
-	} else {
-		return fmt.Errorf("unsupported not")
+	switch node := node.(type) {
+	case *sqlparser.ComparisonExpr:
+		err.Column = columnName(node.Left)
+		err.Operator = node.Operator.ToString()
+	case *sqlparser.BetweenExpr:
+		err.Column = columnName(node.Left)
+		err.Operator = lo.Ternary(node.IsBetween, "between", "not between")
+	case *sqlparser.IsExpr:
+		err.Column = columnName(node.Left)
+		err.Operator = node.Right.ToString()
+	case *sqlparser.ColName:
+		err.Column = columnName(node)
+	case sqlparser.IdentifierCI:
+		err.Column = node.String()
+	case *sqlparser.AndExpr:
+		err.Operator = "and"
+	case *sqlparser.OrExpr:
+		err.Operator = "or"
+	case *sqlparser.NotExpr:
+		err.Operator = "not"
 	}
+
+	return err
 }
 
-func noStringValues(query string) string {
-	stringValsRegex := regexp.MustCompile(`(\"|\').*?(\"|\')`)
-	return stringValsRegex.ReplaceAllString(query, "''")
+func columnName(expr sqlparser.Expr) string {
+	column, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		return ""
+	}
+
+	if column.Qualifier.IsEmpty() {
+		return column.Name.String()
+	}
+	return column.Qualifier.Name.String() + "." + column.Name.String()
 }
@@ -1,6 +1,13 @@
 package filtersql_test
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,6 +15,7 @@ import (
 	"github.com/samber/lo"
 	"github.com/spf13/cast"
 	"github.com/stretchr/testify/assert"
+	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 func commonConfig() fs.Config {
@@ -162,6 +170,22 @@ func commonConfig() fs.Config {
 							},
 						},
 					},
+					NotBetweenOperator: fs.NotBetweenOperator{
+						fs.NotBetweenOperatorFroms{
+							fs.LiteralValue{
+								fs.StringValue{
+									ValidationFunc: func(val string) bool { return cast.ToTime(val) != time.Time{} },
+								},
+							},
+						},
+						fs.NotBetweenOperatorTos{
+							fs.LiteralValue{
+								fs.StringValue{
+									ValidationFunc: func(val string) bool { return cast.ToTime(val) != time.Time{} },
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -488,3 +512,1626 @@ func TestFilterSQLParseGroupingParens(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "e = '(test)' and e = ')' and e = '('", parsedQuery)
 }
+
+func TestFilterSQLParseParams(t *testing.T) {
+	config := commonConfig()
+
+	query := "a = 'test' AND (b IN (2, 4) OR t BETWEEN '2023-05-14 00:00:00' AND '2023-05-14 03:00:00')"
+	parsedQuery, args, err := config.ParseParams(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ? and (b in (?, ?) or t between ? and ?)", parsedQuery)
+	assert.Equal(t, []any{"test", int64(2), int64(4), "2023-05-14 00:00:00", "2023-05-14 03:00:00"}, args)
+
+	query = "a = 'it''s'"
+	parsedQuery, args, err = config.ParseParams(query)
+	assert.EqualError(t, err, "unsupported or invalid RHS: a = 'it\\'s'")
+	assert.Equal(t, "", parsedQuery)
+	assert.Nil(t, args)
+
+	query = ""
+	parsedQuery, args, err = config.ParseParams(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "", parsedQuery)
+	assert.Nil(t, args)
+}
+
+func TestFilterSQLParseDialects(t *testing.T) {
+	config := fs.Config{
+		Allow: fs.Allow{
+			Ands:           fs.UNLIMITED,
+			Ors:            fs.UNLIMITED,
+			Nots:           fs.UNLIMITED,
+			GroupingParens: fs.UNLIMITED,
+			Comparisons: fs.Comparisons{
+				fs.Column{
+					Qualifier: "something",
+					Name:      "order",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.NotEqualsOperatorStringValueAny(),
+					},
+				},
+				fs.Column{
+					Name: "b",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.InOperatorIntegersValueAny(),
+					},
+				},
+			},
+		},
+	}
+	query := "something.`order` != 'it\\'s' AND b IN (1, 2)"
+
+	tests := []struct {
+		dialect    fs.Dialect
+		parsed     string
+		parameters string
+	}{
+		{fs.MySQL, "something.`order` != 'it\\'s' and b in (1, 2)", "something.`order` != ? and b in (?, ?)"},
+		{fs.PostgreSQL, `something."order" <> 'it''s' and b in (1, 2)`, `something."order" <> $1 and b in ($2, $3)`},
+		{fs.SQLite, `something."order" <> 'it''s' and b in (1, 2)`, `something."order" <> ? and b in (?, ?)`},
+		{fs.SQLServer, `something.[order] <> N'it''s' and b in (1, 2)`, `something.[order] <> @p1 and b in (@p2, @p3)`},
+	}
+
+	for _, test := range tests {
+		config.Dialect = test.dialect
+
+		parsedQuery, err := config.Parse(query)
+		assert.NoError(t, err)
+		assert.Equal(t, test.parsed, parsedQuery, test.dialect.String())
+
+		parsedQuery, args, err := config.ParseParams(query)
+		assert.NoError(t, err)
+		assert.Equal(t, test.parameters, parsedQuery, test.dialect.String())
+		assert.Equal(t, []any{"it's", int64(1), int64(2)}, args)
+	}
+
+	config.Allow.Comparisons = fs.Comparisons{
+		fs.Column{Name: "name", ComparisonOperators: fs.ComparisonOperators{fs.EqualsOperatorStringValueAny()}},
+		fs.Column{Name: "freeze", ComparisonOperators: fs.ComparisonOperators{fs.EqualsOperatorStringValueAny()}},
+	}
+	query = "Name = 'héllo' AND FREEZE = 'x'"
+
+	for dialect, expected := range map[fs.Dialect]string{
+		fs.MySQL:      "`name` = 'héllo' and freeze = 'x'",
+		fs.PostgreSQL: `name = 'héllo' and "freeze" = 'x'`,
+		fs.SQLite:     `name = 'héllo' and freeze = 'x'`,
+		fs.SQLServer:  `name = N'héllo' and freeze = N'x'`,
+	} {
+		config.Dialect = dialect
+		parsedQuery, err := config.Parse(query)
+		assert.NoError(t, err, dialect.String())
+		assert.Equal(t, expected, parsedQuery, dialect.String())
+	}
+}
+
+func TestFilterSQLParseTree(t *testing.T) {
+	config := commonConfig()
+
+	query := "NOT (a = 'test' OR something.d = 'test') AND (b IN (2, 4) OR t BETWEEN '2023-05-14 00:00:00' AND '2023-05-14 03:00:00')"
+	tree, err := config.ParseTree(query)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.And{
+		Left: fs.Not{
+			Expr: fs.Or{
+				Left:  fs.Comparison{Column: fs.ColumnRef{Name: "a"}, Operator: "=", Value: "test"},
+				Right: fs.Comparison{Column: fs.ColumnRef{Qualifier: "something", Name: "d"}, Operator: "=", Value: "test"},
+			},
+		},
+		Right: fs.Or{
+			Left:  fs.Comparison{Column: fs.ColumnRef{Name: "b"}, Operator: "in", Value: []any{int64(2), int64(4)}},
+			Right: fs.Between{Column: fs.ColumnRef{Name: "t"}, From: "2023-05-14 00:00:00", To: "2023-05-14 03:00:00"},
+		},
+	}, tree)
+
+	query = "A = 'test' AND something.D = 'test'"
+	tree, err = config.ParseTree(query)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.And{
+		Left:  fs.Comparison{Column: fs.ColumnRef{Name: "a"}, Operator: "=", Value: "test"},
+		Right: fs.Comparison{Column: fs.ColumnRef{Qualifier: "something", Name: "d"}, Operator: "=", Value: "test"},
+	}, tree)
+
+	for _, query := range []string{"c = 'test'", "a", "a = 'test' OR 1", "NOT 0"} {
+		_, parseErr := config.Parse(query)
+		tree, err = config.ParseTree(query)
+		assert.Equal(t, parseErr, err, query)
+		assert.Nil(t, tree, query)
+
+		var filterErr *fs.FilterError
+		assert.ErrorAs(t, err, &filterErr, query)
+	}
+
+	query = ""
+	tree, err = config.ParseTree(query)
+	assert.NoError(t, err)
+	assert.Nil(t, tree)
+}
+
+func TestFilterSQLParseColumnTarget(t *testing.T) {
+	config := fs.Config{
+		Allow: fs.Allow{
+			Ands:           fs.UNLIMITED,
+			GroupingParens: fs.UNLIMITED,
+			Comparisons: fs.Comparisons{
+				fs.Column{
+					Name:   "name",
+					Target: "COALESCE(u.nick, u.name)",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.EqualsOperatorStringValueAny(),
+					},
+				},
+				fs.Column{
+					Name:   "id",
+					Target: "u.user_id",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.InOperatorIntegersValueAny(),
+					},
+				},
+			},
+		},
+	}
+
+	query := "name = 'test' AND id IN (1, 2)"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "COALESCE(u.nick, u.`name`) = 'test' and u.user_id in (1, 2)", parsedQuery)
+
+	config.Dialect = fs.PostgreSQL
+	parsedQuery, args, err := config.ParseParams(query)
+	assert.NoError(t, err)
+	assert.Equal(t, `COALESCE(u.nick, u.name) = $1 and u.user_id in ($2, $3)`, parsedQuery)
+	assert.Equal(t, []any{"test", int64(1), int64(2)}, args)
+
+	query = "u.user_id IN (1, 2)"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported comparison: u.user_id in (1, 2)")
+	assert.Equal(t, "", parsedQuery)
+
+	tree, err := config.ParseTree("id IN (1)")
+	assert.NoError(t, err)
+	assert.Equal(t, fs.Comparison{Column: fs.ColumnRef{Name: "id"}, Operator: "in", Value: []any{int64(1)}}, tree)
+
+	config.Allow.Comparisons = append(config.Allow.Comparisons, fs.Column{
+		Name:   "secret",
+		Target: "(SELECT password FROM users)",
+		ComparisonOperators: fs.ComparisonOperators{
+			fs.EqualsOperatorStringValueAny(),
+		},
+	})
+	parsedQuery, err = config.Parse("secret = 'test'")
+	assert.EqualError(t, err, "invalid column target: (SELECT password FROM users)")
+	assert.Equal(t, "", parsedQuery)
+
+	config.Allow.Comparisons[len(config.Allow.Comparisons)-1] = fs.Column{
+		Name:   "either",
+		Target: "u.a = 1 OR u.b",
+		ComparisonOperators: fs.ComparisonOperators{
+			fs.EqualsOperatorStringValueAny(),
+		},
+	}
+	parsedQuery, err = config.Parse("either = 'test'")
+	assert.EqualError(t, err, "invalid column target: u.a = 1 OR u.b")
+	assert.Equal(t, "", parsedQuery)
+	assert.EqualError(t, config.Validate(), "column either: invalid column target: u.a = 1 OR u.b")
+}
+
+func TestFilterSQLParseFilterError(t *testing.T) {
+	config := commonConfig()
+
+	tests := []struct {
+		query    string
+		code     fs.ErrorCode
+		column   string
+		operator string
+		offset   int
+	}{
+		{"c = 'test'", fs.UnknownColumn, "c", "=", 0},
+		{"a = 'test' AND b = 3", fs.InvalidValue, "b", "=", 15},
+		{"a = 'test' OR\n  something.d = 'fail'", fs.InvalidValue, "something.d", "=", 16},
+		{"a = 'test' OR a > 'test'", fs.UnsupportedOperator, "a", ">", 14},
+		{"a = 'test' OR 'a' = 'a'", fs.SyntaxError, "", "=", 14},
+		{"a = 'test'; select * from passwords", fs.SyntaxError, "", "", 10},
+		{"a = = 'test'", fs.SyntaxError, "", "", 4},
+	}
+
+	for _, test := range tests {
+		_, err := config.Parse(test.query)
+
+		var filterErr *fs.FilterError
+		if assert.ErrorAs(t, err, &filterErr, test.query) {
+			assert.Equal(t, test.code, filterErr.Code, test.query)
+			assert.Equal(t, test.column, filterErr.Column, test.query)
+			assert.Equal(t, test.operator, filterErr.Operator, test.query)
+			assert.Equal(t, test.offset, filterErr.Offset, test.query)
+		}
+	}
+
+	config.Allow.Ands = 0
+	_, err := config.Parse("a = 'test' AND b = 2")
+	var filterErr *fs.FilterError
+	if assert.ErrorAs(t, err, &filterErr) {
+		assert.Equal(t, fs.LimitExceeded, filterErr.Code)
+		assert.Equal(t, "and", filterErr.Operator)
+		assert.Equal(t, "limit_exceeded", filterErr.Code.String())
+	}
+}
+
+func TestFilterSQLParseCollectErrors(t *testing.T) {
+	config := commonConfig()
+
+	query := "c = 'test' AND (a > 'test' OR b = 3) AND b = 2"
+	parsedQuery, err := config.Parse(query)
+	assert.EqualError(t, err, "unsupported comparison: c = 'test'")
+	assert.Equal(t, "", parsedQuery)
+	assert.Len(t, fs.FilterErrors(err), 1)
+
+	config.CollectErrors = true
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported comparison: c = 'test'\nunsupported operator: a > 'test'\nunsupported or invalid RHS: b = 3")
+	assert.Equal(t, "", parsedQuery)
+
+	errs := fs.FilterErrors(err)
+	assert.Equal(t, []fs.ErrorCode{fs.UnknownColumn, fs.UnsupportedOperator, fs.InvalidValue}, lo.Map(errs, func(err *fs.FilterError, _ int) fs.ErrorCode { return err.Code }))
+	assert.Equal(t, []int{0, 16, 30}, lo.Map(errs, func(err *fs.FilterError, _ int) int { return err.Offset }))
+
+	config.Allow.Ors = 0
+	_, err = config.Parse(query)
+	assert.Len(t, fs.FilterErrors(err), 4)
+
+	parsedQuery, err = config.Parse("a = 'test'")
+	assert.NoError(t, err)
+	assert.Equal(t, "a = 'test'", parsedQuery)
+}
+
+func TestFilterSQLParseLimitsFromAST(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Ands = 1
+	config.Allow.Ors = 1
+	config.Allow.Nots = 0
+	config.Allow.GroupingParens = 1
+	config.Allow.TupleParens = 1
+
+	query := "e = 'it\\'s (' && (a NOT IN ('test') || t NOT BETWEEN '2023-05-14' AND '2023-05-15')"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "e = 'it\\'s (' and (a not in ('test') or t not between '2023-05-14' and '2023-05-15')", parsedQuery)
+
+	query = "e = 'x'\nAND e = 'y' AND e = 'z'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported and")
+	assert.Equal(t, "", parsedQuery)
+
+	var filterErr *fs.FilterError
+	assert.ErrorAs(t, err, &filterErr)
+	assert.Equal(t, fs.LimitExceeded, filterErr.Code)
+	assert.Equal(t, "and", filterErr.Operator)
+
+	query = "a = 'test' OR b = 2 || b = 2"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported or")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "NOT a = 'test'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported not")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "((a = 'test'))"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported parens")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "a IN ('test') AND b IN (2)"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported tuple parens")
+	assert.Equal(t, "", parsedQuery)
+}
+
+func TestFilterSQLParseMaxDepthAndPredicates(t *testing.T) {
+	config := commonConfig()
+	config.Allow.MaxDepth = 2
+
+	query := "a = 'test' AND b = 2 AND (a = 'test' OR b = 2 OR b = 2)"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "a = 'test' and b = 2 and (a = 'test' or b = 2 or b = 2)", parsedQuery)
+
+	query = "a = 'test' AND (a = 'test' OR NOT (b = 2 AND b = 2))"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported depth")
+	assert.Equal(t, "", parsedQuery)
+
+	var filterErr *fs.FilterError
+	if assert.ErrorAs(t, err, &filterErr) {
+		assert.Equal(t, fs.LimitExceeded, filterErr.Code)
+		assert.Equal(t, "not", filterErr.Operator)
+		assert.Equal(t, 35, filterErr.Offset)
+	}
+
+	config.Allow.MaxDepth = fs.UNLIMITED
+	config.Allow.MaxPredicates = 2
+
+	query = "a = 'test' AND b IN (2) AND t BETWEEN '2023-05-14' AND '2023-05-15'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported predicates")
+	assert.Equal(t, "", parsedQuery)
+
+	if assert.ErrorAs(t, err, &filterErr) {
+		assert.Equal(t, fs.LimitExceeded, filterErr.Code)
+		assert.Equal(t, 28, filterErr.Offset)
+	}
+}
+
+func TestFilterSQLParseLikeOperators(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons, fs.Column{
+		Name: "nick",
+		ComparisonOperators: fs.ComparisonOperators{
+			fs.LikeOperatorStringValueAny(fs.LikePolicy{MaxWildcards: 1}),
+			fs.NotLikeOperatorStringValue(fs.LikePolicy{
+				LeadingWildcard: true,
+				MaxWildcards:    fs.UNLIMITED,
+				Escapes:         []string{"|"},
+				ValidationFunc:  func(pattern string) bool { return len(pattern) >= 3 },
+			}, func(val string) bool { return val != "%%%" }),
+		},
+	})
+
+	query := "nick LIKE 'te\\_st%'"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "nick like 'te\\_st%'", parsedQuery)
+
+	query = "nick LIKE '%test'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported pattern: nick like '%test'")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "nick LIKE 't_st%'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported pattern: nick like 't_st%'")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "nick LIKE 'test%' ESCAPE '|'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported pattern: nick like 'test%' escape '|'")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "nick NOT LIKE '%te|%st%' ESCAPE '|'"
+	parsedQuery, err = config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "nick not like '%te|%st%' escape '|'", parsedQuery)
+
+	tree, err := config.ParseTree(query)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.Comparison{Column: fs.ColumnRef{Name: "nick"}, Operator: "not like", Value: "%te|%st%", Escape: "|"}, tree)
+
+	query = "nick NOT LIKE '%%'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported pattern: nick not like '%%'")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "nick NOT LIKE '%%%'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported or invalid RHS: nick not like '%%%'")
+	assert.Equal(t, "", parsedQuery)
+
+	config.Dialect = fs.SQLite
+	parsedQuery, args, err := config.ParseParams("nick LIKE 'te\\_st%'")
+	assert.NoError(t, err)
+	assert.Equal(t, "nick like ? escape '\\'", parsedQuery)
+	assert.Equal(t, []any{"te\\_st%"}, args)
+}
+
+func TestFilterSQLParseIsOperators(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name:        "deleted_at",
+			IsOperators: fs.IsOperators{fs.IsNullOperator{}, fs.IsNotNullOperator{}},
+		},
+		fs.Column{
+			Name:        "active",
+			IsOperators: fs.IsOperators{fs.IsTrueOperator{}, fs.IsNotFalseOperator{}},
+		},
+	)
+
+	query := "deleted_at IS NULL AND active IS NOT FALSE"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_at is null and active is not false", parsedQuery)
+
+	tree, err := config.ParseTree(query)
+	assert.NoError(t, err)
+	assert.Equal(t, fs.And{
+		Left:  fs.Is{Column: fs.ColumnRef{Name: "deleted_at"}, Operator: "is null"},
+		Right: fs.Is{Column: fs.ColumnRef{Name: "active"}, Operator: "is not false"},
+	}, tree)
+
+	config.Dialect = fs.SQLServer
+	parsedQuery, err = config.Parse("active IS TRUE OR active IS NOT FALSE")
+	assert.NoError(t, err)
+	assert.Equal(t, "active = 1 or (active <> 0 or active is null)", parsedQuery)
+
+	query = "deleted_at IS TRUE"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported operator: deleted_at is true")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "a IS NULL"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported operator: a is null")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "c IS NULL"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported comparison: c is null")
+	assert.Equal(t, "", parsedQuery)
+}
+
+func TestFilterSQLParseFloatAndDecimalValues(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name: "score",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.GreaterThanOperator{
+					fs.GreaterThanOperatorRights{
+						fs.LiteralValue{fs.FloatValue{ValidationFunc: func(val float64) bool { return val < 1 }}},
+					},
+				},
+				fs.InOperator{
+					fs.InOperatorRights{
+						fs.TupleValue{fs.FloatValues{ValidationFunc: func(vals []float64) bool { return len(vals) <= 2 }}},
+					},
+				},
+			},
+		},
+		fs.Column{
+			Name: "price",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.GreaterThanOperator{
+					fs.GreaterThanOperatorRights{
+						fs.LiteralValue{fs.DecimalValue{
+							MaxPrecision:   5,
+							MaxScale:       2,
+							ValidationFunc: func(val fs.Decimal) bool { return val.Cmp(fs.Decimal{Unscaled: big.NewInt(0)}) >= 0 },
+						}},
+					},
+				},
+				fs.InOperator{
+					fs.InOperatorRights{
+						fs.TupleValue{fs.DecimalValues{
+							MaxPrecision:   fs.UNLIMITED,
+							MaxScale:       0,
+							ValidationFunc: func(vals []fs.Decimal) bool { return true },
+						}},
+					},
+				},
+			},
+		},
+	)
+
+	query := "score > 0.5 AND score > 5e-1 AND score > 0 AND score IN (0.1, 2e3)"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "score > 0.5 and score > 5e-1 and score > 0 and score in (0.1, 2e3)", parsedQuery)
+
+	parsedQuery, args, err := config.ParseParams("score > 5e-1 AND price > 9.99")
+	assert.NoError(t, err)
+	assert.Equal(t, "score > ? and price > ?", parsedQuery)
+	assert.Equal(t, []any{0.5, "9.99"}, args)
+
+	query = "score > 1.5"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported or invalid RHS: score > 1.5")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "score > 'test'"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported or invalid RHS: score > 'test'")
+	assert.Equal(t, "", parsedQuery)
+
+	query = "price > 999.99 AND price > 10 AND price IN (1, 20)"
+	parsedQuery, err = config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "price > 999.99 and price > 10 and price in (1, 20)", parsedQuery)
+
+	for _, query := range []string{"price > 1000.00", "price > 9.999", "price > 1e2", "price IN (1, 2.5)"} {
+		_, err = config.Parse(query)
+		var filterErr *fs.FilterError
+		if assert.ErrorAs(t, err, &filterErr, query) {
+			assert.Equal(t, fs.InvalidValue, filterErr.Code, query)
+		}
+	}
+
+	decimal, err := fs.ParseDecimal("-012.50")
+	assert.NoError(t, err)
+	assert.Equal(t, "-12.50", decimal.String())
+	assert.Equal(t, 4, decimal.Precision())
+	assert.Equal(t, 2, decimal.Scale)
+
+	decimal, err = fs.ParseDecimal(".05")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.05", decimal.String())
+	assert.Equal(t, 2, decimal.Precision())
+
+	for _, invalid := range []string{"", "-", "1.2.3", "+-1", "1e3", "abc"} {
+		_, err = fs.ParseDecimal(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFilterSQLParseSignedNumbers(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons, fs.Column{
+		Name: "n",
+		ComparisonOperators: fs.ComparisonOperators{
+			fs.GreaterThanOperator{
+				fs.GreaterThanOperatorRights{
+					fs.LiteralValue{fs.IntegerValue{ValidationFunc: func(val int) bool { return val >= -10 }}},
+					fs.LiteralValue{fs.FloatValue{ValidationFunc: func(val float64) bool { return val > -1 && val < 0 }}},
+				},
+			},
+			fs.InOperatorIntegersValue(func(vals []int) bool { return lo.Every([]int{-1, 2}, vals) }),
+		},
+	})
+
+	query := "n > -5 AND n > - 10 AND n > -0.5 AND n IN (-1, 2)"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "n > -5 and n > -10 and n > -0.5 and n in (-1, 2)", parsedQuery)
+
+	parsedQuery, args, err := config.ParseParams(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "n > ? and n > ? and n > ? and n in (?, ?)", parsedQuery)
+	assert.Equal(t, []any{int64(-5), int64(-10), "-0.5", int64(-1), int64(2)}, args)
+
+	tree, err := config.ParseTree("n > -5")
+	assert.NoError(t, err)
+	assert.Equal(t, fs.Comparison{Column: fs.ColumnRef{Name: "n"}, Operator: ">", Value: int64(-5)}, tree)
+
+	query = "n > -11"
+	parsedQuery, err = config.Parse(query)
+	assert.EqualError(t, err, "unsupported or invalid RHS: n > -11")
+	assert.Equal(t, "", parsedQuery)
+
+	for _, query := range []string{"n > ~5", "n > - -5", "n > -'5'", "n IN (~1, 2)"} {
+		parsedQuery, err = config.Parse(query)
+		assert.Error(t, err, query)
+		assert.Equal(t, "", parsedQuery)
+	}
+
+	config.CollectErrors = true
+	_, err = config.Parse("n > 1 AND b IN (-3, 2)")
+	if errs := fs.FilterErrors(err); assert.Len(t, errs, 1) {
+		assert.Equal(t, 10, errs[0].Offset)
+	}
+}
+
+func TestFilterSQLParse64BitIntegers(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name: "snowflake",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.EqualsOperator{fs.EqualsOperatorRights{fs.LiteralValue{fs.Uint64Value{ValidationFunc: func(val uint64) bool { return val > 0 }}}}},
+				fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.Uint64Values{ValidationFunc: func(vals []uint64) bool { return len(vals) <= 2 }}}}},
+			},
+		},
+		fs.Column{
+			Name: "offset_ms",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.GreaterThanOperator{fs.GreaterThanOperatorRights{fs.LiteralValue{fs.Int64Value{ValidationFunc: func(val int64) bool { return val != 0 }}}}},
+				fs.NotInOperator{fs.NotInOperatorRights{fs.TupleValue{fs.Int64Values{ValidationFunc: func(vals []int64) bool { return true }}}}},
+			},
+		},
+	)
+
+	tests := []struct {
+		query    string
+		expected string
+		err      string
+	}{
+		{query: "snowflake = 18446744073709551615", expected: "snowflake = 18446744073709551615"},
+		{query: "snowflake in (1, 18446744073709551615)", expected: "snowflake in (1, 18446744073709551615)"},
+		{query: "offset_ms > -9223372036854775808", expected: "offset_ms > -9223372036854775808"},
+		{query: "offset_ms not in (-1, 9223372036854775807)", expected: "offset_ms not in (-1, 9223372036854775807)"},
+		{query: "snowflake = 0", err: "unsupported or invalid RHS: snowflake = 0"},
+		{query: "snowflake in (1, 2, 3)", err: "unsupported or invalid RHS: snowflake in (1, 2, 3)"},
+		{query: "snowflake = 'a'", err: "unsupported or invalid RHS: snowflake = 'a'"},
+		{query: "snowflake = 18446744073709551616", err: "out of range value: snowflake = 18446744073709551616"},
+		{query: "snowflake = -1", err: "out of range value: snowflake = -1"},
+		{query: "snowflake in (1, 18446744073709551616)", err: "out of range value: snowflake in (1, 18446744073709551616)"},
+		{query: "offset_ms > 9223372036854775808", err: "out of range value: offset_ms > 9223372036854775808"},
+		{query: "offset_ms not in (-9223372036854775809)", err: "out of range value: offset_ms not in (-9223372036854775809)"},
+		{query: "b = 99999999999999999999", err: "out of range value: b = 99999999999999999999"},
+		{query: "b IN (2, 99999999999999999999)", err: "out of range value: b in (2, 99999999999999999999)"},
+		{query: "t BETWEEN 1 AND 99999999999999999999", err: "unsupported or invalid RHS: t between 1 and 99999999999999999999"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		if test.err == "" {
+			assert.NoError(t, err, test.query)
+		} else {
+			assert.EqualError(t, err, test.err, test.query)
+			if errs := fs.FilterErrors(err); assert.Len(t, errs, 1, test.query) {
+				assert.Equal(t, fs.InvalidValue, errs[0].Code, test.query)
+			}
+		}
+		assert.Equal(t, test.expected, parsedQuery, test.query)
+	}
+
+	_, args, err := config.ParseParams("snowflake = 18446744073709551615 AND snowflake IN (1, 9223372036854775808)")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"18446744073709551615", int64(1), "9223372036854775808"}, args)
+
+	// database/sql's default converter rejects a uint64 with the high bit set.
+	for _, arg := range args {
+		_, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		assert.NoError(t, err)
+	}
+}
+
+func TestFilterSQLParseBooleanValues(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name: "active",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.EqualsOperator{fs.EqualsOperatorRights{fs.LiteralValue{fs.BooleanValue{AllowIntegers: true, ValidationFunc: func(val bool) bool { return true }}}}},
+				fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.BooleanValues{AllowIntegers: true, ValidationFunc: func(vals []bool) bool { return true }}}}},
+			},
+		},
+		fs.Column{
+			Name: "archived",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.NotEqualsOperator{fs.NotEqualsOperatorRights{fs.LiteralValue{fs.BooleanValue{ValidationFunc: func(val bool) bool { return !val }}}}},
+			},
+		},
+	)
+
+	parsedQuery, err := config.Parse("active = true AND active = 0 AND active IN (1, false) AND archived != false")
+	assert.NoError(t, err)
+	assert.Equal(t, "active = true and active = false and active in (true, false) and archived != false", parsedQuery)
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: "active = 2", err: "unsupported or invalid RHS: active = 2"},
+		{query: "active = 'true'", err: "unsupported or invalid RHS: active = 'true'"},
+		{query: "active IN (1, 2)", err: "unsupported or invalid RHS: active in (1, 2)"},
+		{query: "archived != 0", err: "unsupported or invalid RHS: archived != 0"},
+		{query: "archived != true", err: "unsupported or invalid RHS: archived != true"},
+		{query: "true", err: "unsupported syntax: true"},
+		{query: "NOT true", err: "unsupported syntax: true"},
+		{query: "active = 1 OR true", err: "unsupported syntax: true"},
+		{query: "a = 'test' OR 1", err: "unsupported syntax: 1"},
+		{query: "a = 'test' OR 'x'", err: "unsupported syntax: 'x'"},
+		{query: "NOT 0", err: "unsupported syntax: 0"},
+		{query: "active", err: "unsupported syntax: active"},
+		{query: "a = 'test' AND active", err: "unsupported syntax: active"},
+		{query: "(active = 1, active = 0)", err: "unsupported syntax: (active = 1, active = 0)"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		assert.EqualError(t, err, test.err, test.query)
+		assert.Equal(t, "", parsedQuery, test.query)
+	}
+
+	for dialect, expected := range map[fs.Dialect]string{
+		fs.MySQL:      "active = true and active in (true, false)",
+		fs.PostgreSQL: "active = true and active in (true, false)",
+		fs.SQLite:     "active = 1 and active in (1, 0)",
+		fs.SQLServer:  "active = 1 and active in (1, 0)",
+	} {
+		config.Dialect = dialect
+		parsedQuery, err := config.Parse("active = 1 AND active IN (true, 0)")
+		assert.NoError(t, err, dialect.String())
+		assert.Equal(t, expected, parsedQuery, dialect.String())
+	}
+
+	config.Dialect = fs.PostgreSQL
+	parsedQuery, args, err := config.ParseParams("active = 1 AND archived != false")
+	assert.NoError(t, err)
+	assert.Equal(t, "active = $1 and archived <> $2", parsedQuery)
+	assert.Equal(t, []any{true, false}, args)
+
+	tree, err := config.ParseTree("active = 0")
+	assert.NoError(t, err)
+	assert.Equal(t, fs.Comparison{Column: fs.ColumnRef{Name: "active"}, Operator: "=", Value: false}, tree)
+}
+
+func TestFilterSQLParseDateTimeValues(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name: "born_on",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.GreaterThanOperator{fs.GreaterThanOperatorRights{fs.LiteralValue{fs.DateValue{
+					Layouts: []string{"2006-01-02", "02/01/2006"},
+					Min:     time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+					Max:     time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+				}}}},
+				fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.DateValues{
+					ValidationFunc: func(vals []time.Time) bool { return len(vals) <= 2 },
+				}}}},
+			},
+			BetweenOperator: fs.BetweenOperator{
+				fs.BetweenOperatorFroms{fs.LiteralValue{fs.DateValue{MaxAge: 90 * 24 * time.Hour}}},
+				fs.BetweenOperatorTos{fs.LiteralValue{fs.DateValue{MaxAhead: 24 * time.Hour}}},
+			},
+		},
+		fs.Column{
+			Name: "seen_at",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.LessThanOperator{fs.LessThanOperatorRights{fs.LiteralValue{fs.DateTimeValue{
+					Location: berlin,
+					UTC:      true,
+					ValidationFunc: func(val time.Time) bool {
+						return val.Minute() == 0
+					},
+				}}}},
+			},
+		},
+		fs.Column{
+			Name: "created_at",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.GreaterThanOrEqualOperator{fs.GreaterThanOrEqualOperatorRights{fs.LiteralValue{fs.TimestampValue{}}}},
+				fs.LessThanOrEqualOperator{fs.LessThanOrEqualOperatorRights{fs.LiteralValue{fs.TimestampValue{UTC: true}}}},
+			},
+		},
+	)
+
+	today := time.Now().UTC().Format("2006-01-02")
+	monthAgo := time.Now().UTC().AddDate(0, -1, 0).Format("2006-01-02")
+	yearAgo := time.Now().UTC().AddDate(-1, 0, 0).Format("2006-01-02")
+	nextMonth := time.Now().UTC().AddDate(0, 1, 0).Format("2006-01-02")
+
+	tests := []struct {
+		query    string
+		expected string
+		err      string
+	}{
+		{query: "born_on > '2001-02-03'", expected: "born_on > '2001-02-03'"},
+		{query: "born_on > '03/02/2001'", expected: "born_on > '2001-02-03'"},
+		{query: "born_on > DATE '2001-02-03'", expected: "born_on > date'2001-02-03'"},
+		{query: "born_on IN ('2001-02-03', DATE '2001-02-04')", expected: "born_on in ('2001-02-03', date'2001-02-04')"},
+		{query: "born_on BETWEEN '" + monthAgo + "' AND '" + today + "'", expected: "born_on between '" + monthAgo + "' and '" + today + "'"},
+		{query: "seen_at < '2023-05-14 10:00:00'", expected: "seen_at < '2023-05-14 08:00:00'"},
+		{query: "seen_at < TIMESTAMP '2023-01-14T10:00:00.5'", expected: "seen_at < timestamp'2023-01-14 09:00:00.5'"},
+		{query: "seen_at < TIMESTAMP '2023-01-14 10:00:00.5'", expected: "seen_at < timestamp'2023-01-14 09:00:00.5'"},
+		{query: "created_at >= '2023-05-14T10:00:00+02:00'", expected: "created_at >= '2023-05-14 10:00:00+02:00'"},
+		{query: "created_at >= '2023-05-14 10:00:00'", expected: "created_at >= '2023-05-14 10:00:00'"},
+		{query: "created_at <= TIMESTAMP '2023-05-14 10:00:00-05:00'", expected: "created_at <= timestamp'2023-05-14 15:00:00'"},
+		{query: "born_on > '1899-12-31'", err: "unsupported or invalid RHS: born_on > '1899-12-31'"},
+		{query: "born_on > '2001-02-30'", err: "unsupported or invalid RHS: born_on > '2001-02-30'"},
+		{query: "born_on > 20010203", err: "unsupported or invalid RHS: born_on > 20010203"},
+		{query: "born_on > TIMESTAMP '2001-02-03 00:00:00'", err: "unsupported or invalid RHS: born_on > timestamp'2001-02-03 00:00:00'"},
+		{query: "born_on IN ('2001-02-03', '2001-02-04', '2001-02-05')", err: "unsupported or invalid RHS: born_on in ('2001-02-03', '2001-02-04', '2001-02-05')"},
+		{query: "born_on BETWEEN '" + yearAgo + "' AND '" + today + "'", err: "unsupported or invalid RHS: born_on between '" + yearAgo + "' and '" + today + "'"},
+		{query: "born_on BETWEEN '" + monthAgo + "' AND '" + nextMonth + "'", err: "unsupported or invalid RHS: born_on between '" + monthAgo + "' and '" + nextMonth + "'"},
+		{query: "seen_at < '2023-05-14 10:30:00'", err: "unsupported or invalid RHS: seen_at < '2023-05-14 10:30:00'"},
+		{query: "created_at >= '14 May 2023'", err: "unsupported or invalid RHS: created_at >= '14 May 2023'"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		if test.err == "" {
+			assert.NoError(t, err, test.query)
+		} else {
+			assert.EqualError(t, err, test.err, test.query)
+		}
+		assert.Equal(t, test.expected, parsedQuery, test.query)
+	}
+
+	config.Dialect = fs.PostgreSQL
+	parsedQuery, err := config.Parse("born_on > DATE '2001-02-03' AND seen_at < TIMESTAMP '2023-05-14 10:00:00'")
+	assert.NoError(t, err)
+	assert.Equal(t, "born_on > date '2001-02-03' and seen_at < timestamp '2023-05-14 08:00:00'", parsedQuery)
+}
+
+func TestFilterSQLParseIdentifierValues(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name: "uuid",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.EqualsOperator{fs.EqualsOperatorRights{fs.LiteralValue{fs.UUIDValue{}}}},
+				fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.UUIDValues{}}}},
+			},
+		},
+		fs.Column{
+			Name: "ip",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.EqualsOperator{fs.EqualsOperatorRights{
+					fs.LiteralValue{fs.IPValue{ValidationFunc: func(val netip.Addr) bool { return !val.IsLoopback() }}},
+				}},
+				fs.NotInOperator{fs.NotInOperatorRights{fs.TupleValue{fs.IPValues{}}}},
+			},
+		},
+		fs.Column{
+			Name: "network",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.EqualsOperator{fs.EqualsOperatorRights{
+					fs.LiteralValue{fs.CIDRValue{ValidationFunc: func(val netip.Prefix) bool { return val.Bits() >= 8 }}},
+				}},
+				fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.CIDRValues{}}}},
+			},
+		},
+		fs.Column{
+			Name: "email",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.EqualsOperator{fs.EqualsOperatorRights{
+					fs.LiteralValue{fs.EmailValue{ValidationFunc: func(val string) bool { return strings.HasSuffix(val, "@example.com") }}},
+				}},
+				fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.EmailValues{}}}},
+			},
+		},
+	)
+
+	tests := []struct {
+		query    string
+		expected string
+		err      string
+	}{
+		{query: "uuid = 'F47AC10B-58CC-4372-A567-0E02B2C3D479'", expected: "uuid = 'f47ac10b-58cc-4372-a567-0e02b2c3d479'"},
+		{query: "uuid = 'f47ac10b58cc4372a5670e02b2c3d479'", expected: "uuid = 'f47ac10b-58cc-4372-a567-0e02b2c3d479'"},
+		{query: "uuid IN ('F47AC10B-58CC-4372-A567-0E02B2C3D479', '00000000-0000-0000-0000-000000000000')", expected: "uuid in ('f47ac10b-58cc-4372-a567-0e02b2c3d479', '00000000-0000-0000-0000-000000000000')"},
+		{query: "ip = '2001:DB8:0:0:0:0:0:1'", expected: "ip = '2001:db8::1'"},
+		{query: "ip = '192.168.0.1'", expected: "ip = '192.168.0.1'"},
+		{query: "ip NOT IN ('::FFFF:10.0.0.1', '127.0.0.1')", expected: "ip not in ('::ffff:10.0.0.1', '127.0.0.1')"},
+		{query: "network = '10.1.2.3/8'", expected: "network = '10.0.0.0/8'"},
+		{query: "network IN ('2001:DB8::/32', '192.168.0.0/16')", expected: "network in ('2001:db8::/32', '192.168.0.0/16')"},
+		{query: "email = 'Jane.Doe@EXAMPLE.com'", expected: "email = 'Jane.Doe@example.com'"},
+		{query: "email IN ('a@b.org', 'c@D.org')", expected: "email in ('a@b.org', 'c@d.org')"},
+		{query: "uuid = 'f47ac10b-58cc-4372-a567-0e02b2c3d47'", err: "unsupported or invalid RHS: uuid = 'f47ac10b-58cc-4372-a567-0e02b2c3d47'"},
+		{query: "uuid = 'g47ac10b-58cc-4372-a567-0e02b2c3d479'", err: "unsupported or invalid RHS: uuid = 'g47ac10b-58cc-4372-a567-0e02b2c3d479'"},
+		{query: "uuid IN ('f47ac10b-58cc-4372-a567-0e02b2c3d479', 'x')", err: "unsupported or invalid RHS: uuid in ('f47ac10b-58cc-4372-a567-0e02b2c3d479', 'x')"},
+		{query: "ip = '127.0.0.1'", err: "unsupported or invalid RHS: ip = '127.0.0.1'"},
+		{query: "ip = '256.0.0.1'", err: "unsupported or invalid RHS: ip = '256.0.0.1'"},
+		{query: "ip = 'fe80::1%eth0'", err: "unsupported or invalid RHS: ip = 'fe80::1%eth0'"},
+		{query: "network = '10.0.0.0/4'", err: "unsupported or invalid RHS: network = '10.0.0.0/4'"},
+		{query: "network = '10.0.0.0'", err: "unsupported or invalid RHS: network = '10.0.0.0'"},
+		{query: "email = 'jane@example.org'", err: "unsupported or invalid RHS: email = 'jane@example.org'"},
+		{query: "email = 'Jane <jane@example.com>'", err: "unsupported or invalid RHS: email = 'Jane <jane@example.com>'"},
+		{query: "email IN ('a@b.org', 'c')", err: "unsupported or invalid RHS: email in ('a@b.org', 'c')"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		if test.err == "" {
+			assert.NoError(t, err, test.query)
+		} else {
+			assert.EqualError(t, err, test.err, test.query)
+		}
+		assert.Equal(t, test.expected, parsedQuery, test.query)
+	}
+
+	_, args, err := config.ParseParams("ip = '2001:DB8::1' AND uuid IN ('F47AC10B58CC4372A5670E02B2C3D479')")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"2001:db8::1", "f47ac10b-58cc-4372-a567-0e02b2c3d479"}, args)
+}
+
+func TestFilterSQLParseNotBetweenOperator(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name:            "age",
+			BetweenOperator: fs.BetweenOperatorIntegerValues(func(from, to int) bool { return from <= to && to-from <= 10 }),
+		},
+		fs.Column{
+			Name:               "nick",
+			NotBetweenOperator: fs.NotBetweenOperatorStringValues(func(from, to string) bool { return from < to }),
+		},
+	)
+
+	tests := []struct {
+		query    string
+		expected string
+		err      string
+		code     fs.ErrorCode
+		operator string
+	}{
+		{query: "age BETWEEN 18 AND 28", expected: "age between 18 and 28"},
+		{query: "age BETWEEN -5 AND 5", expected: "age between -5 and 5"},
+		{query: "nick NOT BETWEEN 'a' AND 'm'", expected: "nick not between 'a' and 'm'"},
+		{query: "t NOT BETWEEN '2023-05-14' AND '2023-05-15'", expected: "t not between '2023-05-14' and '2023-05-15'"},
+		{query: "age BETWEEN 28 AND 18", err: "unsupported range: age between 28 and 18", code: fs.InvalidValue, operator: "between"},
+		{query: "age BETWEEN 0 AND 11", err: "unsupported range: age between 0 and 11", code: fs.InvalidValue, operator: "between"},
+		{query: "age BETWEEN 'a' AND 11", err: "unsupported or invalid RHS: age between 'a' and 11", code: fs.InvalidValue, operator: "between"},
+		{query: "age NOT BETWEEN 18 AND 28", err: "unsupported operator: age not between 18 and 28", code: fs.UnsupportedOperator, operator: "not between"},
+		{query: "nick BETWEEN 'a' AND 'm'", err: "unsupported operator: nick between 'a' and 'm'", code: fs.UnsupportedOperator, operator: "between"},
+		{query: "nick NOT BETWEEN 'm' AND 'a'", err: "unsupported range: nick not between 'm' and 'a'", code: fs.InvalidValue, operator: "not between"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		if test.err == "" {
+			assert.NoError(t, err, test.query)
+		} else {
+			assert.EqualError(t, err, test.err, test.query)
+
+			var filterErr *fs.FilterError
+			if assert.ErrorAs(t, err, &filterErr, test.query) {
+				assert.Equal(t, test.code, filterErr.Code, test.query)
+				assert.Equal(t, test.operator, filterErr.Operator, test.query)
+			}
+		}
+		assert.Equal(t, test.expected, parsedQuery, test.query)
+	}
+
+	tree, err := config.ParseTree("nick NOT BETWEEN 'a' AND 'm'")
+	assert.NoError(t, err)
+	assert.Equal(t, fs.NotBetween{Column: fs.ColumnRef{Name: "nick"}, From: "a", To: "m"}, tree)
+}
+
+func TestFilterSQLParseGenericHelpers(t *testing.T) {
+	config := fs.Config{
+		Allow: fs.Allow{
+			Ands: fs.UNLIMITED,
+			Comparisons: fs.Comparisons{
+				fs.Column{
+					Name: "b",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.Equals(fs.Integer(fs.Any[int]())),
+						fs.NotEquals(fs.Value(func(val int) bool { return val != 0 })),
+						fs.GreaterThan(fs.Integer(func(val int) bool { return val >= 0 }), fs.Float(func(val float64) bool { return val >= 0 })),
+						fs.LessThan(fs.Integer(func(val int) bool { return val <= 100 })),
+						fs.GreaterThanOrEqual(fs.Int64(fs.Any[int64]())),
+						fs.LessThanOrEqual(fs.Uint64(fs.Any[uint64]())),
+						fs.InList(fs.Integers(func(vals []int) bool { return len(vals) <= 3 })),
+						fs.NotInList(fs.Values(fs.Any[[]int]())),
+					},
+					BetweenOperator:    fs.BetweenValues(fs.Integer(fs.Any[int]()), fs.Integer(fs.Any[int]())),
+					NotBetweenOperator: fs.NotBetweenValues(fs.Integer(fs.Any[int]()), fs.Integer(fs.Any[int]())),
+				},
+				fs.Column{
+					Name: "nick",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.Equals(fs.String(fs.Any[string]()), fs.UUID(nil)),
+						fs.Like(fs.LikePolicy{MaxWildcards: 1}, fs.String(fs.Any[string]())),
+						fs.NotLike(fs.LikePolicy{LeadingWildcard: true, MaxWildcards: fs.UNLIMITED}, fs.String(fs.Any[string]())),
+						fs.InList(fs.Strings(fs.Any[[]string]()), fs.Emails(nil)),
+					},
+				},
+				fs.Column{
+					Name: "price",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.LessThan(fs.Numeric(5, 2, func(val fs.Decimal) bool { return val.Unscaled.Sign() > 0 })),
+						fs.InList(fs.Values(fs.Any[[]fs.Decimal]())),
+					},
+				},
+				fs.Column{
+					Name: "active",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.Equals(fs.Boolean(fs.Any[bool]())),
+					},
+				},
+				fs.Column{
+					Name: "ip",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.Equals(fs.IP(fs.Any[netip.Addr]()), fs.CIDR(fs.Any[netip.Prefix]())),
+					},
+				},
+				fs.Column{
+					Name: "born_on",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.GreaterThan(fs.Date(nil)),
+						fs.InList(fs.Dates(nil)),
+					},
+				},
+			},
+		},
+	}
+
+	query := "b = 1 AND b != 2 AND b > 0.5 AND b < 100 AND b >= -9223372036854775808 AND b <= 18446744073709551615 AND " +
+		"b IN (1, 2, 3) AND b NOT IN (4) AND b BETWEEN 1 AND 2 AND b NOT BETWEEN 3 AND 4 AND " +
+		"nick = 'F47AC10B-58CC-4372-A567-0E02B2C3D479' AND nick LIKE 'a%' AND nick NOT LIKE '%a%' AND nick IN ('a@B.org', 'x') AND " +
+		"price < 999.99 AND price IN (1.5, 2) AND active = true AND ip = '10.1.0.0/16' AND born_on > '2001-02-03' AND born_on IN ('2001-02-03')"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "b = 1 and b != 2 and b > 0.5 and b < 100 and b >= -9223372036854775808 and b <= 18446744073709551615 and "+
+		"b in (1, 2, 3) and b not in (4) and b between 1 and 2 and b not between 3 and 4 and "+
+		"nick = 'F47AC10B-58CC-4372-A567-0E02B2C3D479' and nick like 'a%' and nick not like '%a%' and nick in ('a@B.org', 'x') and "+
+		"price < 999.99 and price in (1.5, 2) and active = true and ip = '10.1.0.0/16' and born_on > '2001-02-03' and born_on in ('2001-02-03')", parsedQuery)
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: "b != 0", err: "unsupported or invalid RHS: b != 0"},
+		{query: "b > -0.5", err: "unsupported or invalid RHS: b > -0.5"},
+		{query: "b IN (1, 2, 3, 4)", err: "unsupported or invalid RHS: b in (1, 2, 3, 4)"},
+		{query: "nick LIKE '%a%'", err: "unsupported pattern: nick like '%a%'"},
+		{query: "price < 1000.00", err: "unsupported or invalid RHS: price < 1000.00"},
+		{query: "price < 9.999", err: "unsupported or invalid RHS: price < 9.999"},
+		{query: "born_on > '2001-02-30'", err: "unsupported or invalid RHS: born_on > '2001-02-30'"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		assert.EqualError(t, err, test.err, test.query)
+		assert.Equal(t, "", parsedQuery, test.query)
+	}
+
+	assert.Equal(t, fs.InOperator{fs.InOperatorRights{fs.TupleValue{fs.IntegerValues{}}}}, fs.InList(fs.Integers(nil)))
+}
+
+func TestFilterSQLParseTypedColumns(t *testing.T) {
+	config := fs.Config{
+		Allow: fs.Allow{
+			Ands: fs.UNLIMITED,
+			Comparisons: fs.Comparisons{
+				fs.IntColumn("b", fs.Operators(fs.Eq, fs.Gt, fs.Lt, fs.In, fs.Within), fs.Range(0, 100), fs.MaxItems(3)),
+				fs.Uint64Column("snowflake", fs.Operators(fs.Eq, fs.NotIn)),
+				fs.DecimalColumn("price", fs.Operators(fs.Lte), fs.Precision(5, 2), fs.Range(0.01, 999.99)),
+				fs.StringColumn("nick", fs.Qualifier("u"), fs.Target("users.nickname"),
+					fs.Operators(fs.Eq, fs.In, fs.Match, fs.Null), fs.MaxLength(5), fs.Pattern("^[a-z%]+$"),
+					fs.LikeRules(fs.LikePolicy{MaxWildcards: 1})),
+				fs.StringColumn("status", fs.Operators(fs.Eq, fs.NotIn), fs.Enum("open", "closed")),
+				fs.BoolColumn("active", fs.Operators(fs.Eq)),
+				fs.DateColumn("born_on", fs.Operators(fs.Outside), fs.TimeRange(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})),
+			},
+		},
+	}
+
+	query := "b = 0 AND b > 50 AND b < 100 AND b IN (1, 2, 3) AND b BETWEEN 10 AND 20 AND " +
+		"snowflake = 18446744073709551615 AND snowflake NOT IN (1) AND price <= 999.99 AND " +
+		"u.nick = 'abc' AND u.nick IN ('a', 'b') AND u.nick LIKE 'ab%' AND u.nick IS NULL AND " +
+		"`status` = 'open' AND `status` NOT IN ('closed') AND active = false AND born_on NOT BETWEEN '1990-01-01' AND '2000-01-01'"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "b = 0 and b > 50 and b < 100 and b in (1, 2, 3) and b between 10 and 20 and "+
+		"snowflake = 18446744073709551615 and snowflake not in (1) and price <= 999.99 and "+
+		"users.nickname = 'abc' and users.nickname in ('a', 'b') and users.nickname like 'ab%' and users.nickname is null and "+
+		"`status` = 'open' and `status` not in ('closed') and active = false and born_on not between '1990-01-01' and '2000-01-01'", parsedQuery)
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: "b = 101", err: "unsupported or invalid RHS: b = 101"},
+		{query: "b > -1", err: "unsupported or invalid RHS: b > -1"},
+		{query: "b IN (1, 101)", err: "unsupported or invalid RHS: b in (1, 101)"},
+		{query: "b IN (1, 2, 3, 4)", err: "unsupported or invalid RHS: b in (1, 2, 3, 4)"},
+		{query: "b BETWEEN 10 AND 200", err: "unsupported or invalid RHS: b between 10 and 200"},
+		{query: "b != 1", err: "unsupported operator: b != 1"},
+		{query: "b NOT BETWEEN 1 AND 2", err: "unsupported operator: b not between 1 and 2"},
+		{query: "b IS NULL", err: "unsupported operator: b is null"},
+		{query: "snowflake = -1", err: "out of range value: snowflake = -1"},
+		{query: "price <= 0.00", err: "unsupported or invalid RHS: price <= 0.00"},
+		{query: "price <= 1.001", err: "unsupported or invalid RHS: price <= 1.001"},
+		{query: "u.nick = 'abcdef'", err: "unsupported or invalid RHS: u.nick = 'abcdef'"},
+		{query: "u.nick IN ('a', 'B')", err: "unsupported or invalid RHS: u.nick in ('a', 'B')"},
+		{query: "u.nick LIKE 'a%b%'", err: "unsupported pattern: u.nick like 'a%b%'"},
+		{query: "`status` = 'pending'", err: "unsupported or invalid RHS: `status` = 'pending'"},
+		{query: "`status` NOT IN ('open', 'pending')", err: "unsupported or invalid RHS: `status` not in ('open', 'pending')"},
+		{query: "active = 1", err: "unsupported or invalid RHS: active = 1"},
+		{query: "born_on NOT BETWEEN '1800-01-01' AND '2000-01-01'", err: "unsupported or invalid RHS: born_on not between '1800-01-01' and '2000-01-01'"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		assert.EqualError(t, err, test.err, test.query)
+		assert.Equal(t, "", parsedQuery, test.query)
+	}
+
+	config.Allow.Comparisons = fs.Comparisons{fs.StringColumn("tag", fs.Operators(fs.Match, fs.NotMatch))}
+	parsedQuery, err = config.Parse("tag LIKE 'a%b_%' AND tag NOT LIKE 'ab%'")
+	assert.NoError(t, err)
+	assert.Equal(t, "tag like 'a%b_%' and tag not like 'ab%'", parsedQuery)
+
+	_, err = config.Parse("tag LIKE '%ab'")
+	assert.EqualError(t, err, "unsupported pattern: tag like '%ab'")
+
+	column := fs.IntColumn("b", fs.Operators(fs.Eq, fs.In, fs.Within)).Column()
+	assert.Equal(t, "b", column.Name)
+	assert.Len(t, column.ComparisonOperators, 2)
+	assert.NotNil(t, column.BetweenOperator)
+	assert.Nil(t, column.NotBetweenOperator)
+}
+
+func TestFilterSQLParseCustomOperators(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons, fs.Column{
+		Name: "nick",
+		ComparisonOperators: fs.ComparisonOperators{
+			fs.CustomOperator{
+				Operator:       sqlparser.RegexpOp,
+				RightsAccessor: fs.Rights{fs.String(fs.Any[string]())},
+				ValidationFunc: func(node *sqlparser.ComparisonExpr) error {
+					_, err := regexp.Compile(node.Right.(*sqlparser.Literal).Val)
+					return err
+				},
+			},
+			fs.CustomOperator{
+				Operator:       sqlparser.NotRegexpOp,
+				RightsAccessor: fs.Rights{fs.String(fs.Any[string]())},
+			},
+			fs.CustomOperator{
+				Operator:       sqlparser.NullSafeEqualOp,
+				RightsAccessor: fs.Rights{fs.Integer(fs.Any[int]())},
+				ValidationFunc: func(node *sqlparser.ComparisonExpr) error {
+					if sqlparser.String(node.Right) == "0" {
+						return errors.New("zero is not allowed")
+					}
+					return nil
+				},
+			},
+		},
+	})
+
+	query := "nick REGEXP '^a+$' AND nick NOT REGEXP 'b' AND nick <=> 1"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "nick regexp '^a+$' and nick not regexp 'b' and nick <=> 1", parsedQuery)
+
+	config.Dialect = fs.PostgreSQL
+	parsedQuery, err = config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "nick ~ '^a+$' and nick !~ 'b' and nick is not distinct from 1", parsedQuery)
+
+	config.Dialect = fs.SQLServer
+	_, err = config.Parse("nick <=> 1 AND nick REGEXP '^a+$'")
+	assert.EqualError(t, err, "unsupported operator for sqlserver: regexp")
+	if filterErrs := fs.FilterErrors(err); assert.Len(t, filterErrs, 1) {
+		assert.Equal(t, fs.UnsupportedOperator, filterErrs[0].Code)
+		assert.Equal(t, "nick", filterErrs[0].Column)
+		assert.Equal(t, "regexp", filterErrs[0].Operator)
+		assert.Equal(t, 15, filterErrs[0].Offset)
+	}
+	config.Dialect = fs.MySQL
+
+	tests := []struct {
+		query string
+		err   string
+		code  fs.ErrorCode
+	}{
+		{query: "nick REGEXP '('", err: "error parsing regexp: missing closing ): `(`", code: fs.InvalidValue},
+		{query: "nick REGEXP 1", err: "unsupported or invalid RHS: nick regexp 1", code: fs.InvalidValue},
+		{query: "nick <=> 0", err: "zero is not allowed", code: fs.InvalidValue},
+		{query: "nick <=> 'a'", err: "unsupported or invalid RHS: nick <=> 'a'", code: fs.InvalidValue},
+		{query: "nick = 'a'", err: "unsupported operator: nick = 'a'", code: fs.UnsupportedOperator},
+		{query: "a REGEXP 'b'", err: "unsupported operator: a regexp 'b'", code: fs.UnsupportedOperator},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		assert.EqualError(t, err, test.err, test.query)
+		assert.Equal(t, "", parsedQuery, test.query)
+
+		var filterErr *fs.FilterError
+		if assert.ErrorAs(t, err, &filterErr, test.query) {
+			assert.Equal(t, test.code, filterErr.Code, test.query)
+			assert.Equal(t, 0, filterErr.Offset, test.query)
+		}
+	}
+}
+
+type colour string
+
+const (
+	red  colour = "red"
+	blue colour = "blue"
+)
+
+type colourValue struct{}
+
+func (colourValue) Validate(expr sqlparser.Expr) error {
+	value, err := fs.DecodeValue(expr)
+	if err != nil {
+		return err
+	}
+	if colour(cast.ToString(value)) != red && colour(cast.ToString(value)) != blue {
+		return fmt.Errorf("unknown colour: %v", value)
+	}
+	return nil
+}
+
+type coloursValue struct{}
+
+func (coloursValue) Validate(expr sqlparser.Expr) error {
+	values, err := fs.DecodeValue(expr)
+	if err != nil {
+		return err
+	}
+	for _, value := range values.([]any) {
+		if err := (colourValue{}).Validate(sqlparser.NewStrLiteral(cast.ToString(value))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type hexValue struct{}
+
+func (hexValue) Validate(expr sqlparser.Expr) error {
+	literal, ok := expr.(*sqlparser.Literal)
+	if !ok || literal.Type != sqlparser.HexVal {
+		return errors.New("expected a hex literal")
+	}
+	return nil
+}
+
+type anyValue struct{}
+
+func (anyValue) Validate(sqlparser.Expr) error { return nil }
+
+func TestFilterSQLParseCustomValues(t *testing.T) {
+	config := commonConfig()
+	config.Allow.Comparisons = append(config.Allow.Comparisons,
+		fs.Column{
+			Name: "colour",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.Equals(fs.CustomValue{colourValue{}}),
+				fs.InList(fs.CustomValue{coloursValue{}}),
+			},
+			BetweenOperator: fs.BetweenValues(fs.CustomValue{colourValue{}}, fs.CustomValue{colourValue{}}),
+		},
+		fs.Column{
+			Name: "digest",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.Equals(fs.CustomValue{hexValue{}}, fs.String(func(val string) bool { return len(val) == 4 })),
+			},
+		},
+		fs.Column{
+			Name: "x",
+			ComparisonOperators: fs.ComparisonOperators{
+				fs.Equals(fs.CustomValue{anyValue{}}),
+				fs.InList(fs.CustomValue{anyValue{}}),
+			},
+		},
+	)
+
+	query := "colour = 'red' AND colour IN ('red', 'blue') AND colour BETWEEN 'blue' AND 'red' AND digest = X'0A0B' AND digest = 'abcd'"
+	parsedQuery, err := config.Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "colour = 'red' and colour in ('red', 'blue') and colour between 'blue' and 'red' and digest = X'0A0B' and digest = 'abcd'", parsedQuery)
+
+	parsedQuery, err = config.Parse("x = 1 AND x IN ('a', true)")
+	assert.NoError(t, err)
+	assert.Equal(t, "x = 1 and x in ('a', true)", parsedQuery)
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: "colour = 'green'", err: "unknown colour: green"},
+		{query: "colour IN ('red', 'green')", err: "unknown colour: green"},
+		{query: "colour BETWEEN 'red' AND 'green'", err: "unknown colour: green"},
+		{query: "colour = upper('red')", err: "unsupported value: upper('red')"},
+		{query: "digest = 'abc'", err: "expected a hex literal"},
+		{query: "digest = 1", err: "expected a hex literal"},
+		{query: "x = (select password from users limit 1)", err: "unsupported value: (select `password` from users limit 1)"},
+		{query: "x = sleep(10)", err: "unsupported value: sleep(10)"},
+		{query: "x IN (1, sleep(10))", err: "unsupported value: (1, sleep(10))"},
+		{query: "x = a", err: "unsupported value: a"},
+	}
+
+	for _, test := range tests {
+		parsedQuery, err := config.Parse(test.query)
+		assert.EqualError(t, err, test.err, test.query)
+		assert.Equal(t, "", parsedQuery, test.query)
+
+		var filterErr *fs.FilterError
+		if assert.ErrorAs(t, err, &filterErr, test.query) {
+			assert.Equal(t, fs.InvalidValue, filterErr.Code, test.query)
+		}
+	}
+}
+
+func TestFilterSQLLoadConfig(t *testing.T) {
+	config, err := fs.LoadConfig([]byte(`
+dialect: postgresql
+collect_errors: true
+limits:
+  ands: unlimited
+  ors: 1
+  max_predicates: 10
+columns:
+  - name: b
+    type: int
+    operators: [eq, gt, in, between]
+    range: {min: 0, max: 100}
+    max_items: 3
+  - name: nick
+    qualifier: u
+    target: users.nickname
+    type: string
+    operators: [eq, like, is_null]
+    pattern: "^[a-z%]+$"
+    max_length: 5
+    like: {leading_wildcard: false, max_wildcards: 1}
+  - name: status
+    type: string
+    operators: [eq, not_in]
+    enum: [open, closed]
+  - name: price
+    type: decimal
+    operators: [lte]
+    precision: 5
+    scale: 2
+  - name: seen_at
+    type: date
+    operators: [gt]
+    range: {min: "2000-01-01"}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, fs.PostgreSQL, config.Dialect)
+	assert.True(t, config.CollectErrors)
+	assert.Equal(t, fs.UNLIMITED, config.Allow.Ands)
+	assert.Equal(t, 1, config.Allow.Ors)
+	assert.Equal(t, 0, config.Allow.Nots)
+	assert.Equal(t, 10, config.Allow.MaxPredicates)
+
+	parsedQuery, err := config.Parse("b = 1 AND b IN (1, 2) AND b BETWEEN 1 AND 5 AND u.nick LIKE 'ab%' AND u.nick IS NULL AND " +
+		"`status` NOT IN ('closed') AND price <= 999.99 AND seen_at > '2001-01-01'")
+	assert.NoError(t, err)
+	assert.Equal(t, "b = 1 and b in (1, 2) and b between 1 and 5 and users.nickname like 'ab%' and users.nickname is null and "+
+		"status not in ('closed') and price <= 999.99 and seen_at > '2001-01-01'", parsedQuery)
+
+	for query, expected := range map[string]string{
+		"b = 101":                "unsupported or invalid RHS: b = 101",
+		"b IN (1, 2, 3, 4)":      "unsupported or invalid RHS: b in (1, 2, 3, 4)",
+		"u.nick = 'ABC'":         "unsupported or invalid RHS: u.nick = 'ABC'",
+		"x.u.nick = 'ab'":        "unsupported comparison: x.u.nick = 'ab'",
+		"`status` = 'x'":         "unsupported or invalid RHS: `status` = 'x'",
+		"price <= 1.001":         "unsupported or invalid RHS: price <= 1.001",
+		"seen_at > '1999-01-01'": "unsupported or invalid RHS: seen_at > '1999-01-01'",
+		"NOT b = 1":              "unsupported not",
+	} {
+		_, err := config.Parse(query)
+		assert.EqualError(t, err, expected, query)
+	}
+
+	config, err = fs.LoadConfig([]byte(`{"limits": {"ands": 2}, "columns": [{"name": "b", "type": "int", "operators": ["eq"]}]}`))
+	assert.NoError(t, err)
+	parsedQuery, err = config.Parse("b = 1 AND b = 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "b = 1 and b = 2", parsedQuery)
+
+	config, err = fs.LoadConfig([]byte(`{"columns": [{"name": "tag", "type": "string", "operators": ["like"]}]}`))
+	assert.NoError(t, err)
+	parsedQuery, err = config.Parse("tag LIKE 'a%b%'")
+	assert.NoError(t, err)
+	assert.Equal(t, "tag like 'a%b%'", parsedQuery)
+	_, err = config.Parse("tag LIKE '%a'")
+	assert.EqualError(t, err, "unsupported pattern: tag like '%a'")
+
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{config: "dialect: oracle", err: "line 1, column 10: unknown dialect: oracle"},
+		{config: "limit: {}", err: "line 1, column 8: unknown field: limit"},
+		{config: "limits:\n  ands: -2", err: "line 2, column 9: expected a count or unlimited"},
+		{config: "limits:\n  ands: many", err: "line 2, column 9: expected a count or unlimited"},
+		{config: "columns:\n  - name: b\n    type: integer", err: "line 3, column 11: unknown column type: integer"},
+		{config: "columns:\n  - type: int", err: "line 2, column 5: column without a name"},
+		{config: "columns:\n  - name: b\n    type: int\n    operators: [eq, equals]", err: "line 4, column 21: unknown operator: equals"},
+		{config: "columns:\n  - name: b\n    type: string\n    pattern: '('", err: "line 4, column 14: invalid pattern: error parsing regexp: missing closing ): `(`"},
+		{config: "columns:\n  - name: b\n    type: int\n    range: {min: a}", err: "line 4, column 18: invalid min: a"},
+		{config: "columns:\n  - name: b\n    type: string\n    range: {min: 1}", err: "line 4, column 12: range is not supported for string columns"},
+		{config: "columns:\n  - name: b\n    type: date\n    max_age: soon", err: "line 4, column 14: invalid max_age: soon"},
+		{config: "columns:\n  - name: b\n    type: int\n    sort: true", err: "line 4, column 11: unknown column field: sort"},
+		{config: "columns: [b", err: "line 1, column 0: did not find expected ',' or ']'"},
+		{config: "", err: "line 1, column 1: empty config"},
+	}
+
+	for _, test := range tests {
+		_, err := fs.LoadConfig([]byte(test.config))
+		assert.EqualError(t, err, test.err, test.config)
+
+		var configErr *fs.ConfigError
+		assert.ErrorAs(t, err, &configErr, test.config)
+	}
+
+	_, err = fs.LoadConfigFile("does-not-exist.yaml")
+	assert.Error(t, err)
+}
+
+type structAudit struct {
+	CreatedAt time.Time `db:"created_at" filter:"created,ops=gt|lt,min=2000-01-01"`
+}
+
+type structUser struct {
+	structAudit
+	ID       int64   `db:"id" filter:",ops=eq|in|between,min=1"`
+	Status   string  `db:"status_code" filter:"status,ops=eq|in,enum=open|closed"`
+	Name     *string `db:"name" filter:"name,ops=eq|like|is_null,max_length=5,max_wildcards=1"`
+	Nick     string  `filter:"nick,ops=like,leading_wildcard"`
+	Active   bool    `filter:"active,ops=eq"`
+	Token    string  `db:"token" filter:"token,ops=eq,type=uuid"`
+	Password string  `db:"password"`
+	Ignored  int     `filter:"-"`
+}
+
+func TestFilterSQLConfigFromStruct(t *testing.T) {
+	config, err := fs.ConfigFromStruct[structUser](fs.StructOptions{
+		Config: fs.Config{Allow: fs.Allow{Ands: fs.UNLIMITED}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, config.Allow.Comparisons, 7)
+
+	parsedQuery, err := config.Parse("created > '2001-01-01 00:00:00' AND id IN (1, 2) AND id BETWEEN 1 AND 9 AND `status` = 'open' AND " +
+		"name LIKE 'ab%' AND name IS NULL AND active = true AND token = '6BA7B810-9DAD-11D1-80B4-00C04FD430C8' AND nick LIKE '%a%b%'")
+	assert.NoError(t, err)
+	assert.Equal(t, "created_at > '2001-01-01 00:00:00' and id in (1, 2) and id between 1 and 9 and status_code = 'open' and "+
+		"`name` like 'ab%' and `name` is null and active = true and token = '6ba7b810-9dad-11d1-80b4-00c04fd430c8' and nick like '%a%b%'", parsedQuery)
+
+	for query, expected := range map[string]string{
+		"id = 0":                          "unsupported or invalid RHS: id = 0",
+		"`status` = 'x'":                  "unsupported or invalid RHS: `status` = 'x'",
+		"name = 'abcdef'":                 "unsupported or invalid RHS: `name` = 'abcdef'",
+		"created < '1999-01-01 00:00:00'": "unsupported or invalid RHS: created < '1999-01-01 00:00:00'",
+		"token = 'nope'":                  "unsupported or invalid RHS: token = 'nope'",
+		"password = 'x'":                  "unsupported comparison: `password` = 'x'",
+		"active != true":                  "unsupported operator: active != true",
+		"name LIKE 'a%b%'":                "unsupported pattern: `name` like 'a%b%'",
+		"name LIKE '%ab'":                 "unsupported pattern: `name` like '%ab'",
+	} {
+		_, err := config.Parse(query)
+		assert.EqualError(t, err, expected, query)
+	}
+
+	config, err = fs.ConfigFromStruct[structUser](fs.StructOptions{
+		Qualifier: "users",
+	})
+	assert.NoError(t, err)
+	parsedQuery, err = config.Parse("id = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "users.id = 1", parsedQuery)
+
+	_, err = fs.ConfigFromStruct[struct {
+		Tags []string `filter:"tags,ops=in"`
+	}](fs.StructOptions{})
+	assert.EqualError(t, err, "field Tags: unsupported field type: []string")
+
+	_, err = fs.ConfigFromStruct[struct {
+		ID int `filter:"id,ops=equals"`
+	}](fs.StructOptions{})
+	assert.EqualError(t, err, "field ID: unknown operator: equals")
+
+	_, err = fs.ConfigFromStruct[struct {
+		Name string `filter:"name,ops=eq,min=1"`
+	}](fs.StructOptions{})
+	assert.EqualError(t, err, "field Name: range is not supported for string columns")
+
+	_, err = fs.ConfigFromStruct[struct {
+		Name string `filter:"name,ops=like,leading_wildcard=maybe"`
+	}](fs.StructOptions{})
+	assert.EqualError(t, err, "field Name: invalid leading_wildcard: maybe")
+
+	_, err = fs.ConfigFromStruct[int](fs.StructOptions{})
+	assert.EqualError(t, err, "unsupported type: int")
+}
+
+func TestFilterSQLConfigFromDDL(t *testing.T) {
+	ddl := `
+CREATE TABLE users (
+  id BIGINT UNSIGNED NOT NULL,
+  age INT NOT NULL,
+  name VARCHAR(5),
+  created_at DATETIME NOT NULL,
+  price DECIMAL(5,2) NOT NULL,
+  status ENUM('open', 'won''t') NOT NULL,
+  active TINYINT(1) NOT NULL,
+  profile JSON
+);
+CREATE TABLE orders (total INT NOT NULL, secret VARCHAR(10) NOT NULL);`
+
+	config, err := fs.ConfigFromDDL(ddl, fs.DDLOptions{
+		Config:    fs.Config{Allow: fs.Allow{Ands: fs.UNLIMITED}},
+		Exclude:   []string{"orders.secret"},
+		Operators: map[string][]fs.Op{"users.age": {fs.Gt}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, config.Allow.Comparisons, 8)
+
+	parsedQuery, err := config.Parse("id IN (1, 2) AND age > 18 AND name = 'abc' AND name IS NULL AND created_at > '2001-01-01 00:00:00' AND " +
+		"price <= 999.99 AND `status` IN ('open', 'won''t') AND active = true AND total BETWEEN 1 AND 5")
+	assert.NoError(t, err)
+	assert.Equal(t, "id in (1, 2) and age > 18 and `name` = 'abc' and `name` is null and created_at > '2001-01-01 00:00:00' and "+
+		"price <= 999.99 and `status` in ('open', 'won\\'t') and active = true and total between 1 and 5", parsedQuery)
+
+	for query, expected := range map[string]string{
+		"id = -1":             "out of range value: id = -1",
+		"age = 18":            "unsupported operator: age = 18",
+		"name = 'abcdef'":     "unsupported or invalid RHS: `name` = 'abcdef'",
+		"price = 1000.00":     "unsupported or invalid RHS: price = 1000.00",
+		"`status` = 'closed'": "unsupported or invalid RHS: `status` = 'closed'",
+		"active = 2":          "unsupported or invalid RHS: active = 2",
+		"id IS NULL":          "unsupported operator: id is null",
+		"profile = '{}'":      "unsupported comparison: profile = '{}'",
+		"secret = 'x'":        "unsupported comparison: secret = 'x'",
+	} {
+		_, err := config.Parse(query)
+		assert.EqualError(t, err, expected, query)
+	}
+
+	config, err = fs.ConfigFromDDL(ddl, fs.DDLOptions{
+		Columns: []string{"users.id"},
+		Qualify: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, config.Allow.Comparisons, 1)
+	parsedQuery, err = config.Parse("users.id = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "users.id = 1", parsedQuery)
+
+	_, err = fs.ConfigFromDDL(ddl, fs.DDLOptions{Columns: []string{"profile"}})
+	assert.EqualError(t, err, "column profile: unsupported column type: JSON")
+
+	_, err = fs.ConfigFromDDL(ddl, fs.DDLOptions{Columns: []string{"missing"}})
+	assert.EqualError(t, err, "unsupported column: missing")
+
+	_, err = fs.ConfigFromDDL("DROP TABLE users", fs.DDLOptions{})
+	assert.EqualError(t, err, "unsupported statement: drop table users")
+
+	_, err = fs.ConfigFromDDL("CREATE TABLE", fs.DDLOptions{})
+	assert.Error(t, err)
+}
+
+func TestFilterSQLConfigValidate(t *testing.T) {
+	assert.NoError(t, commonConfig().Validate())
+
+	config, err := fs.LoadConfig([]byte("columns:\n  - name: b\n    type: int\n    operators: [eq, in, between, like, is_null]"))
+	assert.NoError(t, err)
+	assert.NoError(t, config.Validate())
+
+	config, err = fs.ConfigFromDDL("CREATE TABLE t (a INT, b VARCHAR(5), c ENUM('x'), d TINYINT(1), e DATE)", fs.DDLOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, config.Validate())
+
+	config = fs.Config{
+		Allow: fs.Allow{
+			Ors: -2,
+			Comparisons: fs.Comparisons{
+				fs.Column{
+					Name: "a",
+					ComparisonOperators: fs.ComparisonOperators{
+						fs.EqualsOperator{RightsAccessor: fs.EqualsOperatorRights{fs.LiteralValue{ValueType: fs.StringValue{}}}},
+						fs.EqualsOperator{RightsAccessor: fs.EqualsOperatorRights{fs.LiteralValue{}}},
+						fs.InOperator{},
+						fs.LikeOperator{
+							RightsAccessor: fs.LikeOperatorRights{fs.LiteralValue{ValueType: fs.IntegerValue{ValidationFunc: fs.Any[int]()}}},
+							Policy:         fs.LikePolicy{MaxWildcards: -3},
+						},
+						fs.CustomOperator{Operator: sqlparser.InOp, RightsAccessor: fs.Rights{fs.String(fs.Any[string]())}},
+						nil,
+					},
+					BetweenOperator: fs.PairedBetweenOperator{BetweenOperator: fs.BetweenOperator{
+						FromsAccessor: fs.BetweenOperatorFroms{fs.Integer(fs.Any[int]())},
+						TosAccessor:   fs.BetweenOperatorTos{fs.Integer(fs.Any[int]())},
+					}},
+				},
+				fs.IntColumn("A", fs.Operators(fs.Eq, fs.Null)),
+				fs.Column{Qualifier: "u", Name: "a", IsOperators: fs.IsOperators{fs.IsNullOperator{}, fs.IsNullOperator{}}},
+			},
+		},
+	}
+
+	err = config.Validate()
+	assert.EqualError(t, err, strings.Join([]string{
+		"invalid limit Ors: -2",
+		"column a: operator =: nil ValidationFunc: LiteralValue{StringValue}",
+		"column a: duplicate operator: =",
+		"column a: operator =: LiteralValue without a ValueType",
+		"column a: operator in allows no values",
+		"column a: invalid limit MaxWildcards: -3",
+		"column a: operator like can never match LiteralValue{IntegerValue}",
+		"column a: duplicate operator: in",
+		"column a: operator in can never match LiteralValue{StringValue}",
+		"column a: nil comparison operator",
+		"column a: operator between: nil ValidationFunc",
+		"duplicate column: A",
+		"column u.a: duplicate operator: is null",
+	}, "\n"))
+}
@@ -6,13 +6,11 @@ require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/samber/lo v1.38.1
 	github.com/spf13/cast v1.5.1
+	gopkg.in/yaml.v3 v3.0.1
 	vitess.io/vitess v0.16.2
 )
 
-require (
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
-)
+require github.com/pmezard/go-difflib v1.0.0 // indirect
 
 require (
 	github.com/DataDog/datadog-agent/pkg/obfuscate v0.42.0 // indirect
@@ -1,203 +1,329 @@
 package filtersql
 
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// Operators
+//
+// Each operator takes the values it accepts on its right hand side, e.g.
+// GreaterThan(Integer(func(val int) bool { return val > 0 })) or
+// InList(Strings(Any[[]string]())).
+
+func Equals(rights ...IEqualsOperatorRight) IComparisonOperator {
+	return EqualsOperator{RightsAccessor: rights}
+}
+
+func NotEquals(rights ...INotEqualsOperatorRight) IComparisonOperator {
+	return NotEqualsOperator{RightsAccessor: rights}
+}
+
+func GreaterThan(rights ...IGreaterThanOperatorRight) IComparisonOperator {
+	return GreaterThanOperator{RightsAccessor: rights}
+}
+
+func LessThan(rights ...ILessThanOperatorRight) IComparisonOperator {
+	return LessThanOperator{RightsAccessor: rights}
+}
+
+func GreaterThanOrEqual(rights ...IGreaterThanOrEqualOperatorRight) IComparisonOperator {
+	return GreaterThanOrEqualOperator{RightsAccessor: rights}
+}
+
+func LessThanOrEqual(rights ...ILessThanOrEqualOperatorRight) IComparisonOperator {
+	return LessThanOrEqualOperator{RightsAccessor: rights}
+}
+
+func InList(rights ...IInOperatorRight) IComparisonOperator {
+	return InOperator{RightsAccessor: rights}
+}
+
+func NotInList(rights ...INotInOperatorRight) IComparisonOperator {
+	return NotInOperator{RightsAccessor: rights}
+}
+
+func Like(policy LikePolicy, rights ...ILikeOperatorRight) IComparisonOperator {
+	return LikeOperator{RightsAccessor: rights, Policy: policy}
+}
+
+func NotLike(policy LikePolicy, rights ...INotLikeOperatorRight) IComparisonOperator {
+	return NotLikeOperator{RightsAccessor: rights, Policy: policy}
+}
+
+func BetweenValues(from IBetweenOperatorFrom, to IBetweenOperatorTo) IBetweenOperator {
+	return BetweenOperator{
+		FromsAccessor: BetweenOperatorFroms{from},
+		TosAccessor:   BetweenOperatorTos{to},
+	}
+}
+
+func NotBetweenValues(from INotBetweenOperatorFrom, to INotBetweenOperatorTo) INotBetweenOperator {
+	return NotBetweenOperator{
+		FromsAccessor: NotBetweenOperatorFroms{from},
+		TosAccessor:   NotBetweenOperatorTos{to},
+	}
+}
+
+// Values
+
+// ValueKind lists the Go types that Value and Values map to a value type.
+// Strings map to StringValue; use UUID, Email, Date and so on for the more
+// specific kinds of string.
+type ValueKind interface {
+	int | int64 | uint64 | float64 | string | bool | Decimal | netip.Addr | netip.Prefix
+}
+
+// Any accepts every value, e.g. Integer(Any[int]()).
+func Any[T any]() func(T) bool {
+	return func(T) bool { return true }
+}
+
+// Value returns the literal value type for T, validated with fun.
+func Value[T ValueKind](fun func(T) bool) LiteralValue {
+	switch fun := any(fun).(type) {
+	case func(int) bool:
+		return LiteralValue{IntegerValue{ValidationFunc: fun}}
+	case func(int64) bool:
+		return LiteralValue{Int64Value{ValidationFunc: fun}}
+	case func(uint64) bool:
+		return LiteralValue{Uint64Value{ValidationFunc: fun}}
+	case func(float64) bool:
+		return LiteralValue{FloatValue{ValidationFunc: fun}}
+	case func(string) bool:
+		return LiteralValue{StringValue{ValidationFunc: fun}}
+	case func(bool) bool:
+		return LiteralValue{BooleanValue{ValidationFunc: fun}}
+	case func(Decimal) bool:
+		return LiteralValue{DecimalValue{MaxPrecision: UNLIMITED, MaxScale: UNLIMITED, ValidationFunc: fun}}
+	case func(netip.Addr) bool:
+		return LiteralValue{IPValue{ValidationFunc: fun}}
+	case func(netip.Prefix) bool:
+		return LiteralValue{CIDRValue{ValidationFunc: fun}}
+	default:
+		panic(fmt.Sprintf("unsupported value kind: %T", fun))
+	}
+}
+
+// Values returns the tuple value type for T, validated with fun.
+func Values[T ValueKind](fun func([]T) bool) TupleValue {
+	switch fun := any(fun).(type) {
+	case func([]int) bool:
+		return TupleValue{IntegerValues{ValidationFunc: fun}}
+	case func([]int64) bool:
+		return TupleValue{Int64Values{ValidationFunc: fun}}
+	case func([]uint64) bool:
+		return TupleValue{Uint64Values{ValidationFunc: fun}}
+	case func([]float64) bool:
+		return TupleValue{FloatValues{ValidationFunc: fun}}
+	case func([]string) bool:
+		return TupleValue{StringValues{ValidationFunc: fun}}
+	case func([]bool) bool:
+		return TupleValue{BooleanValues{ValidationFunc: fun}}
+	case func([]Decimal) bool:
+		return TupleValue{DecimalValues{MaxPrecision: UNLIMITED, MaxScale: UNLIMITED, ValidationFunc: fun}}
+	case func([]netip.Addr) bool:
+		return TupleValue{IPValues{ValidationFunc: fun}}
+	case func([]netip.Prefix) bool:
+		return TupleValue{CIDRValues{ValidationFunc: fun}}
+	default:
+		panic(fmt.Sprintf("unsupported value kind: %T", fun))
+	}
+}
+
+func String(fun func(string) bool) LiteralValue      { return Value(fun) }
+func Strings(fun func([]string) bool) TupleValue     { return Values(fun) }
+func Integer(fun func(int) bool) LiteralValue        { return Value(fun) }
+func Integers(fun func([]int) bool) TupleValue       { return Values(fun) }
+func Int64(fun func(int64) bool) LiteralValue        { return Value(fun) }
+func Int64s(fun func([]int64) bool) TupleValue       { return Values(fun) }
+func Uint64(fun func(uint64) bool) LiteralValue      { return Value(fun) }
+func Uint64s(fun func([]uint64) bool) TupleValue     { return Values(fun) }
+func Float(fun func(float64) bool) LiteralValue      { return Value(fun) }
+func Floats(fun func([]float64) bool) TupleValue     { return Values(fun) }
+func Boolean(fun func(bool) bool) LiteralValue       { return Value(fun) }
+func Booleans(fun func([]bool) bool) TupleValue      { return Values(fun) }
+func IP(fun func(netip.Addr) bool) LiteralValue      { return Value(fun) }
+func IPs(fun func([]netip.Addr) bool) TupleValue     { return Values(fun) }
+func CIDR(fun func(netip.Prefix) bool) LiteralValue  { return Value(fun) }
+func CIDRs(fun func([]netip.Prefix) bool) TupleValue { return Values(fun) }
+
+func Numeric(maxPrecision int, maxScale int, fun func(Decimal) bool) LiteralValue {
+	return LiteralValue{DecimalValue{MaxPrecision: maxPrecision, MaxScale: maxScale, ValidationFunc: fun}}
+}
+
+func Numerics(maxPrecision int, maxScale int, fun func([]Decimal) bool) TupleValue {
+	return TupleValue{DecimalValues{MaxPrecision: maxPrecision, MaxScale: maxScale, ValidationFunc: fun}}
+}
+
+func UUID(fun func(string) bool) LiteralValue  { return LiteralValue{UUIDValue{ValidationFunc: fun}} }
+func UUIDs(fun func([]string) bool) TupleValue { return TupleValue{UUIDValues{ValidationFunc: fun}} }
+
+func Email(fun func(string) bool) LiteralValue  { return LiteralValue{EmailValue{ValidationFunc: fun}} }
+func Emails(fun func([]string) bool) TupleValue { return TupleValue{EmailValues{ValidationFunc: fun}} }
+
+func Date(fun func(time.Time) bool) LiteralValue  { return LiteralValue{DateValue{ValidationFunc: fun}} }
+func Dates(fun func([]time.Time) bool) TupleValue { return TupleValue{DateValues{ValidationFunc: fun}} }
+
+func DateTime(fun func(time.Time) bool) LiteralValue {
+	return LiteralValue{DateTimeValue{ValidationFunc: fun}}
+}
+
+func DateTimes(fun func([]time.Time) bool) TupleValue {
+	return TupleValue{DateTimeValues{ValidationFunc: fun}}
+}
+
+func Timestamp(fun func(time.Time) bool) LiteralValue {
+	return LiteralValue{TimestampValue{ValidationFunc: fun}}
+}
+
+func Timestamps(fun func([]time.Time) bool) TupleValue {
+	return TupleValue{TimestampValues{ValidationFunc: fun}}
+}
+
 // Equals Operator
 
 func EqualsOperatorStringValueAny() IComparisonOperator {
-	return EqualsOperator{
-		RightsAccessor: EqualsOperatorRights{
-			coLiteralStringAny(),
-		},
-	}
+	return Equals(String(Any[string]()))
 }
 
 func EqualsOperatorStringValue(fun func(string) bool) IComparisonOperator {
-	return EqualsOperator{
-		RightsAccessor: EqualsOperatorRights{
-			coLiteralStringValidationFunction(fun),
-		},
-	}
+	return Equals(String(fun))
 }
 
 func EqualsOperatorIntegerValueAny() IComparisonOperator {
-	return EqualsOperator{
-		RightsAccessor: EqualsOperatorRights{
-			coLiteralIntegerAny(),
-		},
-	}
+	return Equals(Integer(Any[int]()))
 }
 
 func EqualsOperatorIntegerValue(fun func(int) bool) IComparisonOperator {
-	return EqualsOperator{
-		RightsAccessor: EqualsOperatorRights{
-			coLiteralIntegerValidationFunction(fun),
-		},
-	}
+	return Equals(Integer(fun))
 }
 
 // Not Equals Operator
 
 func NotEqualsOperatorStringValueAny() IComparisonOperator {
-	return NotEqualsOperator{
-		RightsAccessor: NotEqualsOperatorRights{
-			coLiteralStringAny(),
-		},
-	}
+	return NotEquals(String(Any[string]()))
 }
 
 func NotEqualsOperatorStringValue(fun func(string) bool) IComparisonOperator {
-	return NotEqualsOperator{
-		RightsAccessor: NotEqualsOperatorRights{
-			coLiteralStringValidationFunction(fun),
-		},
-	}
+	return NotEquals(String(fun))
 }
 
 func NotEqualsOperatorIntegerValueAny() IComparisonOperator {
-	return NotEqualsOperator{
-		RightsAccessor: NotEqualsOperatorRights{
-			coLiteralIntegerAny(),
-		},
-	}
+	return NotEquals(Integer(Any[int]()))
 }
 
 func NotEqualsOperatorIntegerValue(fun func(int) bool) IComparisonOperator {
-	return NotEqualsOperator{
-		RightsAccessor: NotEqualsOperatorRights{
-			coLiteralIntegerValidationFunction(fun),
-		},
-	}
+	return NotEquals(Integer(fun))
 }
 
 // In Operator
 
 func InOperatorStringsValueAny() IComparisonOperator {
-	return InOperator{
-		RightsAccessor: InOperatorRights{
-			coTupleStringsAny(),
-		},
-	}
+	return InList(Strings(Any[[]string]()))
 }
 
 func InOperatorStringsValue(fun func([]string) bool) IComparisonOperator {
-	return InOperator{
-		RightsAccessor: InOperatorRights{
-			coTupleStringsValidationFunction(fun),
-		},
-	}
+	return InList(Strings(fun))
 }
 
 func InOperatorIntegersValueAny() IComparisonOperator {
-	return InOperator{
-		RightsAccessor: InOperatorRights{
-			coTupleIntegersAny(),
-		},
-	}
+	return InList(Integers(Any[[]int]()))
 }
 
 func InOperatorIntegersValue(fun func([]int) bool) IComparisonOperator {
-	return InOperator{
-		RightsAccessor: InOperatorRights{
-			coTupleIntegersValidationFunction(fun),
-		},
-	}
+	return InList(Integers(fun))
 }
 
 // Not In Operator
 
 func NotInOperatorStringsValueAny() IComparisonOperator {
-	return NotInOperator{
-		RightsAccessor: NotInOperatorRights{
-			coTupleStringsAny(),
-		},
-	}
+	return NotInList(Strings(Any[[]string]()))
 }
 
 func NotInOperatorStringsValue(fun func([]string) bool) IComparisonOperator {
-	return NotInOperator{
-		RightsAccessor: NotInOperatorRights{
-			coTupleStringsValidationFunction(fun),
-		},
-	}
+	return NotInList(Strings(fun))
 }
 
 func NotInOperatorIntegersValueAny() IComparisonOperator {
-	return NotInOperator{
-		RightsAccessor: NotInOperatorRights{
-			coTupleIntegersAny(),
-		},
-	}
+	return NotInList(Integers(Any[[]int]()))
 }
 
 func NotInOperatorIntegersValue(fun func([]int) bool) IComparisonOperator {
-	return NotInOperator{
-		RightsAccessor: NotInOperatorRights{
-			coTupleIntegersValidationFunction(fun),
-		},
-	}
+	return NotInList(Integers(fun))
 }
 
-// Helpers
+// Like Operator
 
-func coLiteralStringAny() LiteralValue {
-	return LiteralValue{
-		ValueType: StringValue{
-			ValidationFunc: func(s string) bool { return true },
-		},
-	}
+func LikeOperatorStringValueAny(policy LikePolicy) IComparisonOperator {
+	return Like(policy, String(Any[string]()))
 }
 
-func coLiteralStringValidationFunction(fun func(string) bool) LiteralValue {
-	return LiteralValue{
-		ValueType: StringValue{
-			ValidationFunc: fun,
-		},
-	}
+func LikeOperatorStringValue(policy LikePolicy, fun func(string) bool) IComparisonOperator {
+	return Like(policy, String(fun))
 }
 
-func coLiteralIntegerAny() LiteralValue {
-	return LiteralValue{
-		ValueType: IntegerValue{
-			ValidationFunc: func(s int) bool { return true },
-		},
-	}
+// Not Like Operator
+
+func NotLikeOperatorStringValueAny(policy LikePolicy) IComparisonOperator {
+	return NotLike(policy, String(Any[string]()))
 }
 
-func coLiteralIntegerValidationFunction(fun func(int) bool) LiteralValue {
-	return LiteralValue{
-		ValueType: IntegerValue{
-			ValidationFunc: fun,
-		},
-	}
+func NotLikeOperatorStringValue(policy LikePolicy, fun func(string) bool) IComparisonOperator {
+	return NotLike(policy, String(fun))
 }
 
-func coTupleStringsAny() TupleValue {
-	return TupleValue{
-		StringValues{
-			ValidationFunc: func(vals []string) bool { return true },
-		},
-	}
+// Between Operator
+
+func BetweenOperatorStringValues(fun func(from, to string) bool) IBetweenOperator {
+	return coPairedBetween(String(Any[string]()), stringLiteral(coString), fun)
+}
+
+func BetweenOperatorIntegerValues(fun func(from, to int) bool) IBetweenOperator {
+	return coPairedBetween(Integer(Any[int]()), ignoreError(intValue), fun)
+}
+
+// Not Between Operator
+
+func NotBetweenOperatorStringValues(fun func(from, to string) bool) INotBetweenOperator {
+	return coPairedNotBetween(String(Any[string]()), stringLiteral(coString), fun)
+}
+
+func NotBetweenOperatorIntegerValues(fun func(from, to int) bool) INotBetweenOperator {
+	return coPairedNotBetween(Integer(Any[int]()), ignoreError(intValue), fun)
 }
 
-func coTupleStringsValidationFunction(fun func([]string) bool) TupleValue {
-	return TupleValue{
-		StringValues{
-			ValidationFunc: fun,
+// Helpers
+
+func coString(s string) (string, bool) { return s, true }
+
+func coPairedBetween[T any](value LiteralValue, convert func(any) (T, bool), fun func(from, to T) bool) IBetweenOperator {
+	return PairedBetweenOperator{
+		BetweenOperator: BetweenOperator{
+			FromsAccessor: BetweenOperatorFroms{value},
+			TosAccessor:   BetweenOperatorTos{value},
 		},
+		ValidationFunc: coPairValidationFunction(convert, fun),
 	}
 }
 
-func coTupleIntegersAny() TupleValue {
-	return TupleValue{
-		IntegerValues{
-			ValidationFunc: func(vals []int) bool { return true },
+func coPairedNotBetween[T any](value LiteralValue, convert func(any) (T, bool), fun func(from, to T) bool) INotBetweenOperator {
+	return PairedNotBetweenOperator{
+		NotBetweenOperator: NotBetweenOperator{
+			FromsAccessor: NotBetweenOperatorFroms{value},
+			TosAccessor:   NotBetweenOperatorTos{value},
 		},
+		ValidationFunc: coPairValidationFunction(convert, fun),
 	}
 }
 
-func coTupleIntegersValidationFunction(fun func([]int) bool) TupleValue {
-	return TupleValue{
-		IntegerValues{
-			ValidationFunc: fun,
-		},
+func coPairValidationFunction[T any](convert func(any) (T, bool), fun func(from, to T) bool) func(from, to sqlparser.Expr) bool {
+	return func(from, to sqlparser.Expr) bool {
+		fromValue, fromOk := convert(from)
+		toValue, toOk := convert(to)
+		return fromOk && toOk && fun(fromValue, toValue)
 	}
 }
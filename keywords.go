@@ -0,0 +1,95 @@
+package filtersql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Reserved words of each dialect other than MySQL, whose identifiers vitess
+// quotes itself. An identifier that is one of these must be quoted.
+var (
+	postgreSQLKeywords = keywordSet(`
+		all analyse analyze and any array as asc asymmetric authorization binary
+		both case cast check collate collation column concurrently constraint
+		create cross current_catalog current_date current_role current_schema
+		current_time current_timestamp current_user default deferrable desc
+		distinct do else end except false fetch for foreign freeze from full
+		grant group having ilike in initially inner intersect into is isnull join
+		lateral leading left like limit localtime localtimestamp natural not
+		notnull null offset on only or order outer overlaps placing primary
+		references returning right select session_user similar some symmetric
+		system_user table tablesample then to trailing true union unique user
+		using variadic verbose when where window with`)
+
+	sqliteKeywords = keywordSet(`
+		abort action add after all alter always analyze and as asc attach
+		autoincrement before begin between by cascade case cast check collate
+		column commit conflict constraint create cross current current_date
+		current_time current_timestamp database default deferrable deferred
+		delete desc detach distinct do drop each else end escape except exclude
+		exclusive exists explain fail filter first following for foreign from
+		full generated glob group groups having if ignore immediate in index
+		indexed initially inner insert instead intersect into is isnull join key
+		last left like limit match materialized natural no not nothing notnull
+		null nulls of offset on or order others outer over partition plan pragma
+		preceding primary query raise range recursive references regexp reindex
+		release rename replace restrict returning right rollback row rows
+		savepoint select set table temp temporary then ties to transaction
+		trigger unbounded union unique update using vacuum values view virtual
+		when where window with without`)
+
+	sqlServerKeywords = keywordSet(`
+		add all alter and any as asc authorization backup begin between break
+		browse bulk by cascade case check checkpoint close clustered coalesce
+		collate column commit compute constraint contains containstable continue
+		convert create cross current current_date current_time current_timestamp
+		current_user cursor database dbcc deallocate declare default delete deny
+		desc disk distinct distributed double drop dump else end errlvl escape
+		except exec execute exists exit external fetch file fillfactor for
+		foreign freetext freetexttable from full function goto grant group having
+		holdlock identity identity_insert identitycol if in index inner insert
+		intersect into is join key kill left like lineno load merge national
+		nocheck nonclustered not null nullif of off offsets on open
+		opendatasource openquery openrowset openxml option or order outer over
+		percent pivot plan precision primary print proc procedure public
+		raiserror read readtext reconfigure references replication restore
+		restrict return revert revoke right rollback rowcount rowguidcol rule
+		save schema securityaudit select semantickeysquery
+		semanticsimilaritydetailstable semanticsimilaritytable session_user set
+		setuser shutdown some statistics system_user table tablesample textsize
+		then to top tran transaction trigger truncate try_convert tsequal union
+		unique unpivot update updatetext use user values varying view waitfor
+		when where while with within writetext`)
+)
+
+// plainIdentifier matches identifiers that need no quoting in any dialect,
+// reserved words aside.
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func keywordSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// needsQuoting reports whether an identifier must be quoted for the dialect,
+// because it is a reserved word or has characters a plain one can't.
+func (dialect Dialect) needsQuoting(name string) bool {
+	if !plainIdentifier.MatchString(name) {
+		return true
+	}
+
+	word := strings.ToLower(name)
+	switch dialect {
+	case PostgreSQL:
+		return postgreSQLKeywords[word]
+	case SQLite:
+		return sqliteKeywords[word]
+	case SQLServer:
+		return sqlServerKeywords[word]
+	default:
+		return false
+	}
+}
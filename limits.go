@@ -0,0 +1,97 @@
+package filtersql
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// limits counts the nodes of a filter that Allow restricts, as it is walked.
+type limits struct {
+	ands       int
+	ors        int
+	nots       int
+	tuples     int
+	predicates int
+}
+
+func withinLimit(count int, max int) bool {
+	return max == UNLIMITED || count <= max
+}
+
+// withinCap is withinLimit for the opt-in limits, whose zero value leaves
+// them unset rather than allowing none.
+func withinCap(count int, max int) bool {
+	return max == 0 || withinLimit(count, max)
+}
+
+// validateGroupingParens counts the parens of the filter that are not the
+// parens of an IN tuple. vitess drops grouping parens from the AST, so they
+// are counted from the tokens instead.
+func (config Config) validateGroupingParens(filter string, where *sqlparser.Where) error {
+	leftParens := lo.CountBy(tokenize(filter), func(t token) bool { return t.typ == '(' })
+
+	tuples := 0
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if _, ok := node.(sqlparser.ValTuple); ok {
+			tuples++
+		}
+		return true, nil
+	}, where)
+
+	if withinLimit(leftParens-tuples, config.Allow.GroupingParens) {
+		return nil
+	} else {
+		return limitError("", "unsupported parens")
+	}
+}
+
+// validateDepth checks how deeply and, or and not are nested. A run of the
+// same operator, such as a and b and c, is a single level.
+func (config Config) validateDepth(where *sqlparser.Where, offsets offsets) error {
+	var exceeded sqlparser.Expr
+
+	var visit func(expr sqlparser.Expr, parent sqlparser.Expr, depth int)
+	visit = func(expr sqlparser.Expr, parent sqlparser.Expr, depth int) {
+		var children []sqlparser.Expr
+
+		switch node := expr.(type) {
+		case *sqlparser.AndExpr:
+			children = []sqlparser.Expr{node.Left, node.Right}
+		case *sqlparser.OrExpr:
+			children = []sqlparser.Expr{node.Left, node.Right}
+		case *sqlparser.NotExpr:
+			children = []sqlparser.Expr{node.Expr}
+		default:
+			return
+		}
+
+		if _, not := expr.(*sqlparser.NotExpr); not || fmt.Sprintf("%T", expr) != fmt.Sprintf("%T", parent) {
+			depth++
+		}
+
+		if !withinCap(depth, config.Allow.MaxDepth) {
+			exceeded = expr
+			return
+		}
+
+		for _, child := range children {
+			if exceeded == nil {
+				visit(child, expr, depth)
+			}
+		}
+	}
+
+	visit(where.Expr, nil, 0)
+
+	if exceeded == nil {
+		return nil
+	} else {
+		return config.nodeError(LimitExceeded, "unsupported depth", exceeded, offsets)
+	}
+}
+
+func limitError(operator string, message string) error {
+	return &FilterError{Code: LimitExceeded, Operator: operator, Offset: -1, Message: message}
+}
@@ -0,0 +1,442 @@
+package filtersql
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is returned by LoadConfig for an invalid file, with the line
+// and column of the problem. Column is 0 when the YAML parser doesn't report
+// one.
+type ConfigError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// LoadConfigFile reads a Config from a JSON or YAML file. See LoadConfig.
+func LoadConfigFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return LoadConfig(data)
+}
+
+// LoadConfig reads a Config from JSON or YAML, e.g.
+//
+//	dialect: postgresql
+//	collect_errors: true
+//	limits:
+//	  ands: 5
+//	  ors: unlimited
+//	columns:
+//	  - name: status
+//	    type: string
+//	    operators: [eq, in]
+//	    enum: [open, closed]
+//
+// Limits are ands, ors, nots, grouping_parens, tuple_parens, max_depth and
+// max_predicates, each a count or unlimited. Missing ones allow none, except
+// tuple_parens, max_depth and max_predicates, which are then unlimited.
+// Each column has a name and a type, one of int, int64, uint64, float,
+// decimal, string, uuid, email, bool, ip, cidr, date, datetime or timestamp,
+// and optionally a qualifier, a target and the operators it allows: eq, ne,
+// gt, lt, gte, lte, in, not_in, like, not_like, between, not_between,
+// is_null and is_not_null. Values are constrained by range (min and max),
+// enum, pattern, max_length, max_items, max_age (e.g. 90d or 36h), precision
+// and scale, and like patterns by like (leading_wildcard, max_wildcards and
+// escapes), which by default allows any number of wildcards, but not a
+// leading one, and no escape clause.
+func LoadConfig(data []byte) (Config, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return Config{}, yamlError(err)
+	}
+
+	if len(root.Content) == 0 {
+		return Config{}, &ConfigError{Line: 1, Column: 1, Message: "empty config"}
+	}
+
+	config := Config{}
+	err := eachField(root.Content[0], func(key string, node *yaml.Node) error {
+		switch key {
+		case "dialect":
+			return loadDialect(node, &config.Dialect)
+		case "debug":
+			return decodeNode(node, &config.Debug, "a boolean")
+		case "collect_errors":
+			return decodeNode(node, &config.CollectErrors, "a boolean")
+		case "limits":
+			return loadLimits(node, &config.Allow)
+		case "columns":
+			return loadColumns(node, &config.Allow)
+		default:
+			return nodeError(node, "unknown field: %s", key)
+		}
+	})
+
+	return config, err
+}
+
+func loadDialect(node *yaml.Node, dialect *Dialect) error {
+	found, ok := lo.Find([]Dialect{MySQL, PostgreSQL, SQLite, SQLServer}, func(d Dialect) bool {
+		return d.String() == node.Value
+	})
+	if node.Kind != yaml.ScalarNode || !ok {
+		return nodeError(node, "unknown dialect: %s", node.Value)
+	}
+
+	*dialect = found
+	return nil
+}
+
+func loadLimits(node *yaml.Node, allow *Allow) error {
+	limits := map[string]*int{
+		"ands":            &allow.Ands,
+		"ors":             &allow.Ors,
+		"nots":            &allow.Nots,
+		"grouping_parens": &allow.GroupingParens,
+		"tuple_parens":    &allow.TupleParens,
+		"max_depth":       &allow.MaxDepth,
+		"max_predicates":  &allow.MaxPredicates,
+	}
+
+	return eachField(node, func(key string, node *yaml.Node) error {
+		limit, ok := limits[key]
+		if !ok {
+			return nodeError(node, "unknown limit: %s", key)
+		}
+		return decodeLimit(node, limit)
+	})
+}
+
+var columnTypes = map[string]func(string, ...ColumnOption) TypedColumn{
+	"int":       IntColumn,
+	"int64":     Int64Column,
+	"uint64":    Uint64Column,
+	"float":     FloatColumn,
+	"decimal":   DecimalColumn,
+	"string":    StringColumn,
+	"uuid":      UUIDColumn,
+	"email":     EmailColumn,
+	"bool":      BoolColumn,
+	"ip":        IPColumn,
+	"cidr":      CIDRColumn,
+	"date":      DateColumn,
+	"datetime":  DateTimeColumn,
+	"timestamp": TimestampColumn,
+}
+
+var opNames = map[string]Op{
+	"eq":          Eq,
+	"ne":          Ne,
+	"gt":          Gt,
+	"lt":          Lt,
+	"gte":         Gte,
+	"lte":         Lte,
+	"in":          In,
+	"not_in":      NotIn,
+	"like":        Match,
+	"not_like":    NotMatch,
+	"between":     Within,
+	"not_between": Outside,
+	"is_null":     Null,
+	"is_not_null": NotNull,
+}
+
+func loadColumns(node *yaml.Node, allow *Allow) error {
+	if node.Kind != yaml.SequenceNode {
+		return nodeError(node, "expected a list of columns")
+	}
+
+	for _, item := range node.Content {
+		column, err := loadColumn(item)
+		if err != nil {
+			return err
+		}
+		allow.Comparisons = append(allow.Comparisons, column)
+	}
+
+	return nil
+}
+
+func loadColumn(node *yaml.Node) (TypedColumn, error) {
+	var name, columnType string
+	var typeNode, rangeNode *yaml.Node
+	options := []ColumnOption{}
+	precision, scale := UNLIMITED, UNLIMITED
+
+	err := eachField(node, func(key string, node *yaml.Node) error {
+		switch key {
+		case "name":
+			return decodeNode(node, &name, "a string")
+		case "type":
+			typeNode = node
+			return decodeNode(node, &columnType, "a string")
+		case "qualifier", "target":
+			var value string
+			if err := decodeNode(node, &value, "a string"); err != nil {
+				return err
+			}
+			options = append(options, lo.Ternary(key == "qualifier", Qualifier, Target)(value))
+		case "operators":
+			ops, err := loadOperators(node)
+			options = append(options, Operators(ops...))
+			return err
+		case "range":
+			rangeNode = node
+		case "enum":
+			var values []string
+			if err := decodeNode(node, &values, "a list of values"); err != nil {
+				return err
+			}
+			options = append(options, Enum(values...))
+		case "pattern":
+			var expr string
+			if err := decodeNode(node, &expr, "a string"); err != nil {
+				return err
+			}
+			pattern, err := regexp.Compile(expr)
+			if err != nil {
+				return nodeError(node, "invalid pattern: %s", err)
+			}
+			options = append(options, patternOption(pattern))
+		case "max_length", "max_items":
+			var limit int
+			if err := decodeLimit(node, &limit); err != nil {
+				return err
+			}
+			options = append(options, lo.Ternary(key == "max_length", MaxLength, MaxItems)(limit))
+		case "max_age":
+			age, err := parseAge(node.Value)
+			if node.Kind != yaml.ScalarNode || err != nil {
+				return nodeError(node, "invalid max_age: %s", node.Value)
+			}
+			options = append(options, MaxAge(age))
+		case "precision":
+			return decodeLimit(node, &precision)
+		case "scale":
+			return decodeLimit(node, &scale)
+		case "like":
+			policy, err := loadLikePolicy(node)
+			options = append(options, LikeRules(policy))
+			return err
+		default:
+			return nodeError(node, "unknown column field: %s", key)
+		}
+		return nil
+	})
+	if err != nil {
+		return TypedColumn{}, err
+	}
+
+	if name == "" {
+		return TypedColumn{}, nodeError(node, "column without a name")
+	}
+
+	constructor, ok := columnTypes[columnType]
+	if !ok {
+		return TypedColumn{}, nodeError(lo.Ternary(typeNode != nil, typeNode, node), "unknown column type: %s", columnType)
+	}
+
+	if rangeNode != nil {
+		option, err := loadRange(rangeNode, columnType)
+		if err != nil {
+			return TypedColumn{}, err
+		}
+		options = append(options, option)
+	}
+
+	options = append(options, Precision(precision, scale))
+	return constructor(name, options...), nil
+}
+
+func loadOperators(node *yaml.Node) ([]Op, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, nodeError(node, "expected a list of operators")
+	}
+
+	ops := []Op{}
+	for _, item := range node.Content {
+		op, ok := opNames[item.Value]
+		if item.Kind != yaml.ScalarNode || !ok {
+			return nil, nodeError(item, "unknown operator: %s", item.Value)
+		}
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+func loadRange(node *yaml.Node, columnType string) (ColumnOption, error) {
+	bounds := map[string]string{}
+	boundNodes := map[string]*yaml.Node{}
+	err := eachField(node, func(key string, node *yaml.Node) error {
+		if key != "min" && key != "max" {
+			return nodeError(node, "unknown range field: %s", key)
+		}
+		if node.Kind != yaml.ScalarNode {
+			return nodeError(node, "invalid %s: %s", key, node.Value)
+		}
+		bounds[key], boundNodes[key] = node.Value, node
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	option, err := parseRange(columnType, bounds)
+	var boundErr *boundError
+	if errors.As(err, &boundErr) {
+		return nil, nodeError(boundNodes[boundErr.key], "%s", err)
+	} else if err != nil {
+		return nil, nodeError(node, "%s", err)
+	}
+	return option, nil
+}
+
+// boundError reports a min or max that doesn't parse for its column type.
+type boundError struct {
+	key   string
+	value string
+}
+
+func (err *boundError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", err.key, err.value)
+}
+
+// parseRange builds the Range or TimeRange option of a column type from its
+// min and max bounds as written, for the loader, ConfigFromStruct and
+// ConfigFromDDL alike.
+func parseRange(columnType string, bounds map[string]string) (ColumnOption, error) {
+	switch columnType {
+	case "int", "int64", "uint64", "float", "decimal":
+		rats := map[string]*big.Rat{}
+		for _, key := range []string{"min", "max"} {
+			if bound, ok := bounds[key]; ok {
+				rat, ok := new(big.Rat).SetString(bound)
+				if !ok {
+					return nil, &boundError{key: key, value: bound}
+				}
+				rats[key] = rat
+			}
+		}
+		return ratRange(rats["min"], rats["max"]), nil
+	case "date", "datetime", "timestamp":
+		times := map[string]time.Time{}
+		for _, key := range []string{"min", "max"} {
+			if bound, ok := bounds[key]; ok {
+				value, ok := parseBound(bound)
+				if !ok {
+					return nil, &boundError{key: key, value: bound}
+				}
+				times[key] = value
+			}
+		}
+		return TimeRange(times["min"], times["max"]), nil
+	default:
+		return nil, fmt.Errorf("range is not supported for %s columns", columnType)
+	}
+}
+
+func loadLikePolicy(node *yaml.Node) (LikePolicy, error) {
+	policy := defaultLikePolicy
+	err := eachField(node, func(key string, node *yaml.Node) error {
+		switch key {
+		case "leading_wildcard":
+			return decodeNode(node, &policy.LeadingWildcard, "a boolean")
+		case "max_wildcards":
+			return decodeLimit(node, &policy.MaxWildcards)
+		case "escapes":
+			return decodeNode(node, &policy.Escapes, "a list of strings")
+		default:
+			return nodeError(node, "unknown like field: %s", key)
+		}
+	})
+	return policy, err
+}
+
+// eachField calls fun for each key of a mapping, in order, with the value
+// node.
+func eachField(node *yaml.Node, fun func(string, *yaml.Node) error) error {
+	if node.Kind != yaml.MappingNode {
+		return nodeError(node, "expected a mapping")
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if err := fun(node.Content[i].Value, node.Content[i+1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeNode(node *yaml.Node, value any, expected string) error {
+	if err := node.Decode(value); err != nil {
+		return nodeError(node, "expected %s", expected)
+	}
+	return nil
+}
+
+// decodeLimit reads a count, or unlimited as UNLIMITED.
+func decodeLimit(node *yaml.Node, limit *int) error {
+	if node.Kind == yaml.ScalarNode && node.Value == "unlimited" {
+		*limit = UNLIMITED
+		return nil
+	}
+
+	if err := node.Decode(limit); err != nil || *limit < UNLIMITED {
+		return nodeError(node, "expected a count or unlimited")
+	}
+	return nil
+}
+
+// parseAge reads a duration, which may also be given in days, e.g. 90d.
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		return time.Duration(count) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(value)
+}
+
+func parseBound(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func nodeError(node *yaml.Node, format string, args ...any) *ConfigError {
+	return &ConfigError{Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlError converts a YAML syntax error, which only has a line.
+func yamlError(err error) error {
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &ConfigError{Message: err.Error()}
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	return &ConfigError{Line: line, Message: match[2]}
+}
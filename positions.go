@@ -0,0 +1,138 @@
+package filtersql
+
+import (
+	"errors"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+type token struct {
+	typ        int
+	val        string
+	start, end int
+}
+
+func tokenize(filter string) []token {
+	tokenizer := sqlparser.NewStringTokenizer(filter)
+	tokens := []token{}
+	end := 0
+
+	for {
+		typ, val := tokenizer.Scan()
+		if typ == 0 || typ == sqlparser.LEX_ERROR {
+			return tokens
+		}
+
+		start := end
+		for start < tokenizer.Pos && strings.ContainsRune(" \t\r\n", rune(filter[start])) {
+			start++
+		}
+		end = tokenizer.Pos
+
+		tokens = append(tokens, token{typ: typ, val: val, start: start, end: end})
+	}
+}
+
+// offsets maps the columns and literals of a parsed filter to the byte offset
+// at which they start in the filter. vitess does not record positions, so
+// they are recovered by matching the nodes, in order, against the tokens.
+type offsets map[sqlparser.Expr]int
+
+func newOffsets(filter string, expr sqlparser.Expr) offsets {
+	tokens := tokenize(filter)
+	result := offsets{}
+	cursor := 0
+
+	match := func(node sqlparser.Expr, matches func(token) bool) {
+		for i := cursor; i < len(tokens); i++ {
+			if matches(tokens[i]) {
+				result[node] = tokens[i].start
+				cursor = i + 1
+				return
+			}
+		}
+	}
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			first := node.Name.String()
+			if !node.Qualifier.IsEmpty() {
+				first = node.Qualifier.Name.String()
+			}
+			match(node, func(t token) bool {
+				return !isStringToken(t) && strings.EqualFold(t.val, first)
+			})
+			return false, nil
+		case *sqlparser.Literal:
+			// Signed literals are folded from a - token and the number.
+			val := node.Val
+			if node.Type != sqlparser.StrVal {
+				val = strings.TrimPrefix(val, "-")
+			}
+
+			match(node, func(t token) bool {
+				return isStringToken(t) == (node.Type == sqlparser.StrVal) && t.val == val
+			})
+
+			if val != node.Val && cursor > 1 && tokens[cursor-2].typ == '-' {
+				result[node] = tokens[cursor-2].start
+			}
+		}
+		return true, nil
+	}, expr)
+
+	return result
+}
+
+// of returns the offset of the first column or literal within node.
+func (o offsets) of(node sqlparser.SQLNode) int {
+	offset := -1
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if offset != -1 {
+			return false, nil
+		}
+
+		switch node := node.(type) {
+		case *sqlparser.ColName, *sqlparser.Literal:
+			if value, found := o[node.(sqlparser.Expr)]; found {
+				offset = value
+			}
+		}
+		return true, nil
+	}, node)
+
+	return offset
+}
+
+// syntaxErrorOffset returns the offset of the token at which vitess stopped
+// parsing sql, which is filter behind a prefix of prefixLength bytes.
+func syntaxErrorOffset(filter string, sql string, prefixLength int) int {
+	tokenizer := sqlparser.NewStringTokenizer(sql)
+	if sqlparser.ParseTokenizer(tokenizer) == 0 {
+		return -1
+	}
+
+	var positionedErr sqlparser.PositionedErr
+	if !errors.As(tokenizer.LastError, &positionedErr) {
+		return -1
+	}
+
+	end := positionedErr.Pos - 1 - prefixLength
+	for _, t := range tokenize(filter) {
+		if t.end == end {
+			return t.start
+		}
+	}
+
+	if end < 0 || end > len(filter) {
+		return -1
+	}
+	return end
+}
+
+func isStringToken(t token) bool {
+	return t.typ == sqlparser.STRING || t.typ == sqlparser.NCHAR_STRING
+}
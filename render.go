@@ -0,0 +1,193 @@
+package filtersql
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// renderer formats a validated filter for a Dialect, optionally swapping
+// literals for placeholders and collecting them as arguments.
+type renderer struct {
+	dialect Dialect
+	params  bool
+	args    []any
+	err     error
+}
+
+func render(expr sqlparser.Expr, dialect Dialect) (string, error) {
+	r := &renderer{dialect: dialect}
+	result := r.render(expr)
+	if r.err != nil {
+		return "", r.err
+	}
+	return result, nil
+}
+
+func renderParams(expr sqlparser.Expr, dialect Dialect) (string, []any, error) {
+	r := &renderer{dialect: dialect, params: true, args: []any{}}
+	result := r.render(expr)
+	if r.err != nil {
+		return "", nil, r.err
+	}
+	return result, r.args, nil
+}
+
+func (r *renderer) render(expr sqlparser.Expr) string {
+	buf := sqlparser.NewTrackedBuffer(r.format)
+	buf.Myprintf("%v", expr)
+	return buf.String()
+}
+
+func (r *renderer) format(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode) {
+	switch node := node.(type) {
+	case *sqlparser.Literal:
+		if r.params {
+			r.args = append(r.args, literalArg(node))
+			buf.WriteString(r.dialect.placeholder(len(r.args)))
+		} else {
+			r.formatLiteral(buf, node)
+		}
+	case sqlparser.BoolVal:
+		if r.params {
+			r.args = append(r.args, bool(node))
+			buf.WriteString(r.dialect.placeholder(len(r.args)))
+		} else {
+			r.formatBool(buf, node)
+		}
+	case sqlparser.IdentifierCI:
+		r.formatIdentifier(buf, node, node.String())
+	case sqlparser.IdentifierCS:
+		r.formatIdentifier(buf, node, node.String())
+	case *sqlparser.ComparisonExpr:
+		operator, err := r.dialect.comparisonOperator(node.Operator)
+		if err != nil && r.err == nil {
+			r.err = &FilterError{Code: UnsupportedOperator, Operator: node.Operator.ToString(), Offset: -1, Message: err.Error()}
+		}
+		buf.Myprintf("%v %s %v", node.Left, operator, node.Right)
+		if node.Escape != nil {
+			buf.Myprintf(" escape %v", node.Escape)
+		} else if r.dialect.needsLikeEscape(node) {
+			buf.WriteString(" escape " + r.dialect.quoteString("\\"))
+		}
+	case *sqlparser.IsExpr:
+		r.formatIs(buf, node)
+	default:
+		node.Format(buf)
+	}
+}
+
+// formatIs spells out the boolean IS predicates for SQL Server, which only
+// supports IS NULL and IS NOT NULL.
+func (r *renderer) formatIs(buf *sqlparser.TrackedBuffer, node *sqlparser.IsExpr) {
+	if r.dialect != SQLServer {
+		node.Format(buf)
+		return
+	}
+
+	switch node.Right {
+	case sqlparser.IsTrueOp:
+		buf.Myprintf("%v = 1", node.Left)
+	case sqlparser.IsFalseOp:
+		buf.Myprintf("%v = 0", node.Left)
+	case sqlparser.IsNotTrueOp:
+		buf.Myprintf("(%v <> 1 or %v is null)", node.Left, node.Left)
+	case sqlparser.IsNotFalseOp:
+		buf.Myprintf("(%v <> 0 or %v is null)", node.Left, node.Left)
+	default:
+		node.Format(buf)
+	}
+}
+
+// formatBool writes booleans as 1 and 0 where they are stored as integers.
+func (r *renderer) formatBool(buf *sqlparser.TrackedBuffer, node sqlparser.BoolVal) {
+	switch r.dialect {
+	case SQLite, SQLServer:
+		if node {
+			buf.WriteString("1")
+		} else {
+			buf.WriteString("0")
+		}
+	default:
+		node.Format(buf)
+	}
+}
+
+func (r *renderer) formatIdentifier(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode, name string) {
+	if r.dialect == MySQL {
+		node.Format(buf)
+		return
+	}
+
+	// Quote only reserved words and special characters, so plain names keep
+	// the database's case folding.
+	if name != "" && r.dialect.needsQuoting(name) {
+		buf.WriteString(r.dialect.quoteIdentifier(name))
+	} else {
+		buf.WriteString(name)
+	}
+}
+
+func (r *renderer) formatLiteral(buf *sqlparser.TrackedBuffer, literal *sqlparser.Literal) {
+	if r.dialect == MySQL {
+		literal.Format(buf)
+		return
+	}
+
+	switch literal.Type {
+	case sqlparser.StrVal:
+		buf.WriteString(r.dialect.quoteString(literal.Val))
+	case sqlparser.HexVal:
+		switch r.dialect {
+		case PostgreSQL:
+			buf.WriteString(`'\x` + literal.Val + `'::bytea`)
+		case SQLServer:
+			buf.WriteString("0x" + literal.Val)
+		default:
+			literal.Format(buf)
+		}
+	case sqlparser.DateVal, sqlparser.TimeVal, sqlparser.TimestampVal:
+		if r.dialect == PostgreSQL {
+			buf.WriteString(typedLiteralKeyword(literal.Type) + " " + r.dialect.quoteString(literal.Val))
+		} else {
+			buf.WriteString(r.dialect.quoteString(literal.Val))
+		}
+	default:
+		literal.Format(buf)
+	}
+}
+
+func typedLiteralKeyword(valType sqlparser.ValType) string {
+	switch valType {
+	case sqlparser.DateVal:
+		return "date"
+	case sqlparser.TimeVal:
+		return "time"
+	default:
+		return "timestamp"
+	}
+}
+
+// literalArg converts a literal into the Go value a database/sql driver
+// expects for it. Values that cannot be represented exactly stay strings,
+// and so do integers above math.MaxInt64, as database/sql's default
+// converter rejects a uint64 with the high bit set.
+func literalArg(literal *sqlparser.Literal) any {
+	switch literal.Type {
+	case sqlparser.IntVal:
+		if i, err := strconv.ParseInt(literal.Val, 10, 64); err == nil {
+			return i
+		}
+	case sqlparser.FloatVal:
+		if f, err := strconv.ParseFloat(literal.Val, 64); err == nil {
+			return f
+		}
+	case sqlparser.HexVal:
+		if b, err := hex.DecodeString(literal.Val); err == nil {
+			return b
+		}
+	}
+
+	return literal.Val
+}
@@ -0,0 +1,215 @@
+package filtersql
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// StructOptions configures ConfigFromStruct.
+type StructOptions struct {
+	// Config is the base, with the limits and dialect, that gets a column
+	// for every tagged field.
+	Config Config
+	// Qualifier, when set, qualifies every db column, e.g. users.
+	Qualifier string
+}
+
+// ConfigFromStruct derives a Config from the filter tags of T's fields, e.g.
+//
+//	type User struct {
+//		Status string `db:"status_code" filter:"status,ops=eq|in,enum=open|closed"`
+//	}
+//
+// The tag's first part is the public name, by default the db tag or else the
+// field name, and ops lists the allowed operators by the names LoadConfig
+// uses. The value type follows the field's Go type, e.g. int64 gives an
+// Int64Column and time.Time a DateTimeColumn, and type overrides it with one
+// of LoadConfig's column types, e.g. type=uuid. enum, min, max, max_length
+// and max_items constrain the values, with enum values separated by |, and
+// leading_wildcard and max_wildcards the like patterns, which otherwise get
+// LoadConfig's default. A limit of -1 is unlimited. A db tag that differs
+// from the public name becomes the column's Target. Fields without a filter
+// tag, or tagged "-", are skipped, and embedded structs are included.
+func ConfigFromStruct[T any](opts StructOptions) (Config, error) {
+	config := opts.Config
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return Config{}, fmt.Errorf("unsupported type: %s", structType)
+	}
+
+	columns, err := structColumns(structType, opts)
+	if err != nil {
+		return Config{}, err
+	}
+
+	for _, column := range columns {
+		config.Allow.Comparisons = append(config.Allow.Comparisons, column)
+	}
+
+	return config, nil
+}
+
+func structColumns(structType reflect.Type, opts StructOptions) ([]TypedColumn, error) {
+	columns := []TypedColumn{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		tag, tagged := field.Tag.Lookup("filter")
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			embedded, err := structColumns(field.Type, opts)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+
+		if !tagged || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		column, err := structColumn(field, tag, opts)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func structColumn(field reflect.StructField, tag string, opts StructOptions) (TypedColumn, error) {
+	parts := strings.Split(tag, ",")
+	dbName := strings.Split(field.Tag.Get("db"), ",")[0]
+
+	name := parts[0]
+	if name == "" {
+		name = dbName
+	}
+	if name == "" {
+		name = field.Name
+	}
+
+	columnType, err := structColumnType(field.Type)
+	options := []ColumnOption{}
+	bounds := map[string]string{}
+	like := defaultLikePolicy
+
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, "=")
+
+		switch key {
+		case "ops":
+			for _, opName := range strings.Split(value, "|") {
+				op, ok := opNames[opName]
+				if !ok {
+					return TypedColumn{}, fmt.Errorf("unknown operator: %s", opName)
+				}
+				options = append(options, Operators(op))
+			}
+		case "type":
+			columnType, err = value, nil
+		case "enum":
+			options = append(options, Enum(strings.Split(value, "|")...))
+		case "min", "max":
+			bounds[key] = value
+		case "max_length", "max_items", "max_wildcards":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < UNLIMITED {
+				return TypedColumn{}, fmt.Errorf("invalid %s: %s", key, value)
+			}
+			switch key {
+			case "max_length":
+				options = append(options, MaxLength(limit))
+			case "max_items":
+				options = append(options, MaxItems(limit))
+			default:
+				like.MaxWildcards = limit
+			}
+		case "leading_wildcard":
+			// A bare leading_wildcard allows them.
+			allowed, err := strconv.ParseBool(lo.Ternary(value == "", "true", value))
+			if err != nil {
+				return TypedColumn{}, fmt.Errorf("invalid %s: %s", key, value)
+			}
+			like.LeadingWildcard = allowed
+		default:
+			return TypedColumn{}, fmt.Errorf("unknown filter tag option: %s", key)
+		}
+	}
+
+	if err != nil {
+		return TypedColumn{}, err
+	}
+
+	constructor, ok := columnTypes[columnType]
+	if !ok {
+		return TypedColumn{}, fmt.Errorf("unknown column type: %s", columnType)
+	}
+
+	if len(bounds) > 0 {
+		option, err := parseRange(columnType, bounds)
+		if err != nil {
+			return TypedColumn{}, err
+		}
+		options = append(options, option)
+	}
+
+	options = append(options, LikeRules(like))
+
+	if dbName != "" && (dbName != name || opts.Qualifier != "") {
+		options = append(options, Target(strings.TrimPrefix(opts.Qualifier+"."+dbName, ".")))
+	}
+
+	return constructor(name, options...), nil
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	addrType    = reflect.TypeOf(netip.Addr{})
+	prefixType  = reflect.TypeOf(netip.Prefix{})
+	decimalType = reflect.TypeOf(Decimal{})
+)
+
+// structColumnType returns the LoadConfig column type for a Go type.
+// Pointers are followed, so nullable fields map like their values.
+func structColumnType(fieldType reflect.Type) (string, error) {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType {
+	case timeType:
+		return "datetime", nil
+	case addrType:
+		return "ip", nil
+	case prefixType:
+		return "cidr", nil
+	case decimalType:
+		return "decimal", nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return "int", nil
+	case reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "int64", nil
+	case reflect.Uint, reflect.Uint64:
+		return "uint64", nil
+	case reflect.Float32, reflect.Float64:
+		return "float", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "bool", nil
+	default:
+		return "", fmt.Errorf("unsupported field type: %s", fieldType)
+	}
+}
@@ -0,0 +1,172 @@
+package filtersql
+
+import (
+	"fmt"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// Expr is a node of a validated filter, as returned by ParseTree. It is one
+// of And, Or, Not, Comparison, Between, NotBetween or Is.
+type Expr interface {
+	iExpr()
+}
+
+type (
+	And struct {
+		Left, Right Expr
+	}
+	Or struct {
+		Left, Right Expr
+	}
+	Not struct {
+		Expr Expr
+	}
+	ColumnRef struct {
+		Qualifier string
+		Name      string
+	}
+	// Comparison holds a scalar Value such as a string or int64, or a []any
+	// for the in and not in operators. Escape is only set for like and not
+	// like with an ESCAPE clause.
+	Comparison struct {
+		Column   ColumnRef
+		Operator string
+		Value    any
+		Escape   string
+	}
+	Between struct {
+		Column   ColumnRef
+		From, To any
+	}
+	NotBetween struct {
+		Column   ColumnRef
+		From, To any
+	}
+	// Is holds an operator such as "is null" or "is not true".
+	Is struct {
+		Column   ColumnRef
+		Operator string
+	}
+)
+
+func (And) iExpr()        {}
+func (Or) iExpr()         {}
+func (Not) iExpr()        {}
+func (Comparison) iExpr() {}
+func (Between) iExpr()    {}
+func (NotBetween) iExpr() {}
+func (Is) iExpr()         {}
+
+// ParseTree validates the filter in the same way as Parse, but returns it as
+// an Expr tree instead of a string, with each ColumnRef holding the matched
+// column's configured Qualifier and Name. An empty filter returns a nil Expr.
+func (config Config) ParseTree(filter string) (Expr, error) {
+	where, err := config.parse(filter)
+	if err != nil || where == nil {
+		return nil, err
+	}
+
+	return config.buildTree(where.Expr)
+}
+
+// buildTree converts an expression that parse has validated, so it only
+// meets the nodes validateAST accepts.
+func (config Config) buildTree(expr sqlparser.Expr) (Expr, error) {
+	switch node := expr.(type) {
+	case *sqlparser.AndExpr:
+		left, right, err := config.buildTreePair(node.Left, node.Right)
+		if err != nil {
+			return nil, err
+		}
+		return And{Left: left, Right: right}, nil
+	case *sqlparser.OrExpr:
+		left, right, err := config.buildTreePair(node.Left, node.Right)
+		if err != nil {
+			return nil, err
+		}
+		return Or{Left: left, Right: right}, nil
+	case *sqlparser.NotExpr:
+		inner, err := config.buildTree(node.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: inner}, nil
+	case *sqlparser.ComparisonExpr:
+		if column, ok := node.Left.(*sqlparser.ColName); ok {
+			comparison := Comparison{
+				Column:   config.columnRef(column),
+				Operator: node.Operator.ToString(),
+				Value:    treeValue(node.Right),
+			}
+			if escape, ok := node.Escape.(*sqlparser.Literal); ok {
+				comparison.Escape = escape.Val
+			}
+			return comparison, nil
+		}
+	case *sqlparser.IsExpr:
+		if column, ok := node.Left.(*sqlparser.ColName); ok {
+			return Is{
+				Column:   config.columnRef(column),
+				Operator: node.Right.ToString(),
+			}, nil
+		}
+	case *sqlparser.BetweenExpr:
+		if column, ok := node.Left.(*sqlparser.ColName); ok && !node.IsBetween {
+			return NotBetween{
+				Column: config.columnRef(column),
+				From:   treeValue(node.From),
+				To:     treeValue(node.To),
+			}, nil
+		} else if ok {
+			return Between{
+				Column: config.columnRef(column),
+				From:   treeValue(node.From),
+				To:     treeValue(node.To),
+			}, nil
+		}
+	}
+
+	return nil, &FilterError{Code: SyntaxError, Offset: -1, Message: fmt.Sprintf("unsupported syntax: %s", sqlparser.String(expr))}
+}
+
+func (config Config) buildTreePair(left, right sqlparser.Expr) (Expr, Expr, error) {
+	leftExpr, err := config.buildTree(left)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rightExpr, err := config.buildTree(right)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return leftExpr, rightExpr, nil
+}
+
+// columnRef refers to the configured column rather than the filter's
+// spelling of it, as columns match case-insensitively.
+func (config Config) columnRef(lhs *sqlparser.ColName) ColumnRef {
+	column, _ := config.findColumn(lhs)
+	return ColumnRef{
+		Qualifier: column.Qualifier,
+		Name:      column.Name,
+	}
+}
+
+func treeValue(expr sqlparser.Expr) any {
+	switch node := expr.(type) {
+	case *sqlparser.Literal:
+		return literalArg(node)
+	case sqlparser.BoolVal:
+		return bool(node)
+	case sqlparser.ValTuple:
+		values := make([]any, 0, len(node))
+		for _, item := range node {
+			values = append(values, treeValue(item))
+		}
+		return values
+	default:
+		return sqlparser.String(expr)
+	}
+}
@@ -0,0 +1,331 @@
+package filtersql
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
+)
+
+// Op names an operator that a TypedColumn allows.
+type Op int
+
+const (
+	Eq Op = iota
+	Ne
+	Gt
+	Lt
+	Gte
+	Lte
+	In
+	NotIn
+	// Match and NotMatch are like and not like, restricted by LikeRules, or
+	// else to patterns that don't start with a wildcard.
+	Match
+	NotMatch
+	// Within and Outside are between and not between.
+	Within
+	Outside
+	Null
+	NotNull
+)
+
+// TypedColumn declares a column's value type and constraints once, and
+// expands them into the Column with a right hand side for each of its
+// operators. Build one with IntColumn, StringColumn and so on, e.g.
+// IntColumn("b", Operators(Eq, Gt, Lt, In), Range(0, 100)).
+type TypedColumn struct {
+	column Column
+}
+
+func (TypedColumn) iLeft() {}
+
+// Column returns the Column the TypedColumn expands to.
+func (tc TypedColumn) Column() Column { return tc.column }
+
+// ColumnOption configures a TypedColumn. Options that don't apply to the
+// column's type are ignored.
+type ColumnOption func(*columnOptions)
+
+type columnOptions struct {
+	qualifier string
+	target    string
+	operators []Op
+	min       *big.Rat
+	max       *big.Rat
+	enum      []string
+	pattern   *regexp.Regexp
+	maxLength int
+	maxItems  int
+	like      LikePolicy
+	notBefore time.Time
+	notAfter  time.Time
+	maxAge    time.Duration
+	precision int
+	scale     int
+}
+
+// Operators sets the operators the column allows. Without it, none are.
+func Operators(ops ...Op) ColumnOption {
+	return func(opts *columnOptions) { opts.operators = append(opts.operators, ops...) }
+}
+
+// Qualifier sets the column's table qualifier.
+func Qualifier(qualifier string) ColumnOption {
+	return func(opts *columnOptions) { opts.qualifier = qualifier }
+}
+
+// Target sets the SQL expression rendered in place of the column.
+func Target(target string) ColumnOption {
+	return func(opts *columnOptions) { opts.target = target }
+}
+
+// Range bounds numeric values, inclusively.
+func Range[T int | int64 | uint64 | float64](min, max T) ColumnOption {
+	minRat, _ := new(big.Rat).SetString(fmt.Sprint(min))
+	maxRat, _ := new(big.Rat).SetString(fmt.Sprint(max))
+	return ratRange(minRat, maxRat)
+}
+
+// ratRange bounds numeric values, inclusively. A nil bound leaves that side
+// unbounded.
+func ratRange(min, max *big.Rat) ColumnOption {
+	return func(opts *columnOptions) { opts.min, opts.max = min, max }
+}
+
+// Enum restricts string and numeric values to the ones given.
+func Enum[T int | int64 | uint64 | string](values ...T) ColumnOption {
+	return func(opts *columnOptions) {
+		opts.enum = lo.Map(values, func(value T, _ int) string { return fmt.Sprint(value) })
+	}
+}
+
+// Pattern restricts string values to those matching the regular expression.
+// It panics if the expression doesn't compile.
+func Pattern(expr string) ColumnOption {
+	return patternOption(regexp.MustCompile(expr))
+}
+
+func patternOption(pattern *regexp.Regexp) ColumnOption {
+	return func(opts *columnOptions) { opts.pattern = pattern }
+}
+
+// MaxLength limits the length of string values, in characters.
+func MaxLength(length int) ColumnOption {
+	return func(opts *columnOptions) { opts.maxLength = length }
+}
+
+// MaxItems limits the number of values in an in or not in list.
+func MaxItems(items int) ColumnOption {
+	return func(opts *columnOptions) { opts.maxItems = items }
+}
+
+// LikeRules sets the policy for Match and NotMatch patterns. Without it,
+// they get defaultLikePolicy.
+func LikeRules(policy LikePolicy) ColumnOption {
+	return func(opts *columnOptions) { opts.like = policy }
+}
+
+// TimeRange bounds date and time values, inclusively. A zero time leaves
+// that side unbounded.
+func TimeRange(notBefore, notAfter time.Time) ColumnOption {
+	return func(opts *columnOptions) { opts.notBefore, opts.notAfter = notBefore, notAfter }
+}
+
+// MaxAge rejects date and time values older than age.
+func MaxAge(age time.Duration) ColumnOption {
+	return func(opts *columnOptions) { opts.maxAge = age }
+}
+
+// Precision limits the precision and scale of decimal values.
+func Precision(precision, scale int) ColumnOption {
+	return func(opts *columnOptions) { opts.precision, opts.scale = precision, scale }
+}
+
+func IntColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, Integer, Integers, func(opts columnOptions) func(int) bool {
+		return numberRule(opts, func(value int) *big.Rat { return big.NewRat(int64(value), 1) })
+	})
+}
+
+func Int64Column(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, Int64, Int64s, func(opts columnOptions) func(int64) bool {
+		return numberRule(opts, func(value int64) *big.Rat { return big.NewRat(value, 1) })
+	})
+}
+
+func Uint64Column(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, Uint64, Uint64s, func(opts columnOptions) func(uint64) bool {
+		return numberRule(opts, func(value uint64) *big.Rat { return new(big.Rat).SetInt(new(big.Int).SetUint64(value)) })
+	})
+}
+
+func FloatColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, Float, Floats, func(opts columnOptions) func(float64) bool {
+		return numberRule(opts, func(value float64) *big.Rat { return new(big.Rat).SetFloat64(value) })
+	})
+}
+
+func DecimalColumn(name string, options ...ColumnOption) TypedColumn {
+	opts := newColumnOptions(options)
+	return typedColumn(name, options,
+		func(fun func(Decimal) bool) LiteralValue { return Numeric(opts.precision, opts.scale, fun) },
+		func(fun func([]Decimal) bool) TupleValue { return Numerics(opts.precision, opts.scale, fun) },
+		func(opts columnOptions) func(Decimal) bool { return numberRule(opts, Decimal.Rat) },
+	)
+}
+
+func StringColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, String, Strings, func(opts columnOptions) func(string) bool { return opts.stringRule })
+}
+
+func UUIDColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, UUID, UUIDs, func(opts columnOptions) func(string) bool { return opts.stringRule })
+}
+
+func EmailColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, Email, Emails, func(opts columnOptions) func(string) bool { return opts.stringRule })
+}
+
+func BoolColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, Boolean, Booleans, func(columnOptions) func(bool) bool { return Any[bool]() })
+}
+
+func IPColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, IP, IPs, func(columnOptions) func(netip.Addr) bool { return Any[netip.Addr]() })
+}
+
+func CIDRColumn(name string, options ...ColumnOption) TypedColumn {
+	return typedColumn(name, options, CIDR, CIDRs, func(columnOptions) func(netip.Prefix) bool { return Any[netip.Prefix]() })
+}
+
+func DateColumn(name string, options ...ColumnOption) TypedColumn {
+	opts := newColumnOptions(options)
+	return typedColumn(name, options,
+		func(fun func(time.Time) bool) LiteralValue {
+			return LiteralValue{DateValue{Min: opts.notBefore, Max: opts.notAfter, MaxAge: opts.maxAge, ValidationFunc: fun}}
+		},
+		func(fun func([]time.Time) bool) TupleValue {
+			return TupleValue{DateValues{Min: opts.notBefore, Max: opts.notAfter, MaxAge: opts.maxAge, ValidationFunc: fun}}
+		},
+		func(columnOptions) func(time.Time) bool { return Any[time.Time]() },
+	)
+}
+
+func DateTimeColumn(name string, options ...ColumnOption) TypedColumn {
+	opts := newColumnOptions(options)
+	return typedColumn(name, options,
+		func(fun func(time.Time) bool) LiteralValue {
+			return LiteralValue{DateTimeValue{Min: opts.notBefore, Max: opts.notAfter, MaxAge: opts.maxAge, ValidationFunc: fun}}
+		},
+		func(fun func([]time.Time) bool) TupleValue {
+			return TupleValue{DateTimeValues{Min: opts.notBefore, Max: opts.notAfter, MaxAge: opts.maxAge, ValidationFunc: fun}}
+		},
+		func(columnOptions) func(time.Time) bool { return Any[time.Time]() },
+	)
+}
+
+func TimestampColumn(name string, options ...ColumnOption) TypedColumn {
+	opts := newColumnOptions(options)
+	return typedColumn(name, options,
+		func(fun func(time.Time) bool) LiteralValue {
+			return LiteralValue{TimestampValue{Min: opts.notBefore, Max: opts.notAfter, MaxAge: opts.maxAge, ValidationFunc: fun}}
+		},
+		func(fun func([]time.Time) bool) TupleValue {
+			return TupleValue{TimestampValues{Min: opts.notBefore, Max: opts.notAfter, MaxAge: opts.maxAge, ValidationFunc: fun}}
+		},
+		func(columnOptions) func(time.Time) bool { return Any[time.Time]() },
+	)
+}
+
+// defaultLikePolicy allows any number of wildcards, as long as the pattern
+// doesn't start with one, so that it can still use an index.
+var defaultLikePolicy = LikePolicy{MaxWildcards: UNLIMITED}
+
+func newColumnOptions(options []ColumnOption) columnOptions {
+	opts := columnOptions{maxLength: UNLIMITED, maxItems: UNLIMITED, precision: UNLIMITED, scale: UNLIMITED, like: defaultLikePolicy}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// typedColumn builds the Column, validating every value with the same rule,
+// both on its own and as part of a tuple.
+func typedColumn[T any](
+	name string,
+	options []ColumnOption,
+	literal func(func(T) bool) LiteralValue,
+	tuple func(func([]T) bool) TupleValue,
+	rule func(columnOptions) func(T) bool,
+) TypedColumn {
+	opts := newColumnOptions(options)
+	valid := rule(opts)
+
+	value := literal(valid)
+	values := tuple(func(values []T) bool {
+		return withinLimit(len(values), opts.maxItems) && lo.EveryBy(values, valid)
+	})
+
+	// Like patterns aren't values of the column, so only their length is
+	// checked here, and the rest is left to LikeRules.
+	pattern := String(func(value string) bool { return withinLimit(utf8.RuneCountInString(value), opts.maxLength) })
+
+	column := Column{Qualifier: opts.qualifier, Name: name, Target: opts.target}
+	for _, op := range lo.Uniq(opts.operators) {
+		switch op {
+		case Eq:
+			column.ComparisonOperators = append(column.ComparisonOperators, Equals(value))
+		case Ne:
+			column.ComparisonOperators = append(column.ComparisonOperators, NotEquals(value))
+		case Gt:
+			column.ComparisonOperators = append(column.ComparisonOperators, GreaterThan(value))
+		case Lt:
+			column.ComparisonOperators = append(column.ComparisonOperators, LessThan(value))
+		case Gte:
+			column.ComparisonOperators = append(column.ComparisonOperators, GreaterThanOrEqual(value))
+		case Lte:
+			column.ComparisonOperators = append(column.ComparisonOperators, LessThanOrEqual(value))
+		case In:
+			column.ComparisonOperators = append(column.ComparisonOperators, InList(values))
+		case NotIn:
+			column.ComparisonOperators = append(column.ComparisonOperators, NotInList(values))
+		case Match:
+			column.ComparisonOperators = append(column.ComparisonOperators, Like(opts.like, pattern))
+		case NotMatch:
+			column.ComparisonOperators = append(column.ComparisonOperators, NotLike(opts.like, pattern))
+		case Within:
+			column.BetweenOperator = BetweenValues(value, value)
+		case Outside:
+			column.NotBetweenOperator = NotBetweenValues(value, value)
+		case Null:
+			column.IsOperators = append(column.IsOperators, IsNullOperator{})
+		case NotNull:
+			column.IsOperators = append(column.IsOperators, IsNotNullOperator{})
+		}
+	}
+
+	return TypedColumn{column: column}
+}
+
+// numberRule checks Range and Enum.
+func numberRule[T any](opts columnOptions, toRat func(T) *big.Rat) func(T) bool {
+	return func(value T) bool {
+		rat := toRat(value)
+		return rat != nil &&
+			(opts.min == nil || rat.Cmp(opts.min) >= 0) &&
+			(opts.max == nil || rat.Cmp(opts.max) <= 0) &&
+			(opts.enum == nil || lo.Contains(opts.enum, fmt.Sprint(value)))
+	}
+}
+
+// stringRule checks MaxLength, Pattern and Enum.
+func (opts columnOptions) stringRule(value string) bool {
+	return withinLimit(utf8.RuneCountInString(value), opts.maxLength) &&
+		(opts.pattern == nil || opts.pattern.MatchString(value)) &&
+		(opts.enum == nil || lo.Contains(opts.enum, value))
+}
@@ -1,14 +1,25 @@
 package filtersql
 
 import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/samber/lo"
-	"github.com/spf13/cast"
 	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 type Config struct {
-	Allow Allow
-	Debug bool
+	Allow   Allow
+	Dialect Dialect
+	Debug   bool
+	// CollectErrors keeps validating after the first problem, so that every
+	// problem in the filter is returned at once. Use FilterErrors to list them.
+	CollectErrors bool
 }
 
 type Allow struct {
@@ -17,6 +28,15 @@ type Allow struct {
 	Ands           int
 	Nots           int
 	GroupingParens int
+	// TupleParens limits the number of in and not in lists. Unlike the
+	// limits above, it and the ones below are unset, and so unlimited, when
+	// zero.
+	TupleParens int
+	// MaxDepth limits how deeply and, or and not may be nested, where a run
+	// of the same operator counts once. MaxPredicates limits the number of
+	// comparisons in the filter.
+	MaxDepth      int
+	MaxPredicates int
 }
 
 type (
@@ -52,19 +72,85 @@ type (
 		Rights() Rights
 	}
 
+	// comparisonValidator is implemented by comparison operators that
+	// check the comparison as a whole, beyond its RHS value.
+	comparisonValidator interface {
+		validComparison(*sqlparser.ComparisonExpr) bool
+	}
+
+	// comparisonChecker is implemented by CustomOperator, whose checks
+	// explain their own failures.
+	comparisonChecker interface {
+		checkComparison(*sqlparser.ComparisonExpr) error
+	}
+
+	// valueChecker is implemented by value types defined outside the
+	// package, whose checks explain their own failures.
+	valueChecker interface {
+		checkValue(any) error
+	}
+
+	// rangeChecker is implemented by value types that can tell a value of
+	// their kind which doesn't fit apart from one which is simply invalid.
+	rangeChecker interface {
+		outOfRange(any) bool
+	}
+
+	// nodeTypeMatcher is implemented by value types that accept more than
+	// one kind of node, such as booleans written as true or 1.
+	nodeTypeMatcher interface {
+		matchesNodeType(string) bool
+	}
+
+	// funcRequirer is implemented by value types and operators whose
+	// ValidationFunc is required, so that Validate can report a nil one.
+	funcRequirer interface {
+		missingFunc() bool
+	}
+
+	// canonicalizer is implemented by value types that rewrite an accepted
+	// value into its canonical form before rendering.
+	canonicalizer interface {
+		canonical(sqlparser.Expr) sqlparser.Expr
+	}
+
+	IsOperators []IIsOperator
+	IIsOperator interface {
+		iIsOperator()
+		ToString() string
+	}
+
 	IBetweenOperator interface {
 		iBetweenOperator()
 		ToString() string
 		Froms() Froms
 		Tos() Tos
 	}
+
+	INotBetweenOperator interface {
+		iNotBetweenOperator()
+		ToString() string
+		Froms() Froms
+		Tos() Tos
+	}
+
+	// betweenValidator is implemented by between operators that check the
+	// from and to values as a pair.
+	betweenValidator interface {
+		validBetween(*sqlparser.BetweenExpr) bool
+	}
 )
 
 type Column struct {
-	Qualifier           string
-	Name                string
+	Qualifier string
+	Name      string
+	// Target, when set, is the SQL expression rendered in place of the public
+	// Qualifier and Name, e.g. "users.user_id" or "COALESCE(u.nick, u.name)".
+	Target              string
 	ComparisonOperators ComparisonOperators
 	BetweenOperator     IBetweenOperator
+	NotBetweenOperator  INotBetweenOperator
+	IsOperators         IsOperators
 }
 
 func (Column) iLeft() {}
@@ -253,6 +339,121 @@ func (eo NotInOperator) Rights() Rights {
 	})
 }
 
+// LikeOperator
+type (
+	ILikeOperator interface {
+		iLikeOperator()
+	}
+	LikeOperatorRights []ILikeOperatorRight
+	ILikeOperatorRight interface {
+		iLikeOperatorRight()
+		Right() Right
+	}
+	LikeOperator struct {
+		RightsAccessor LikeOperatorRights
+		Policy         LikePolicy
+	}
+)
+
+func (LikeOperator) ToString() string     { return "like" }
+func (LikeOperator) iComparisonOperator() {}
+func (eo LikeOperator) Rights() Rights {
+	return lo.Map(eo.RightsAccessor, func(item ILikeOperatorRight, index int) Right {
+		return item.Right()
+	})
+}
+func (eo LikeOperator) validComparison(node *sqlparser.ComparisonExpr) bool {
+	return eo.Policy.valid(node)
+}
+
+// NotLikeOperator
+type (
+	INotLikeOperator interface {
+		iNotLikeOperator()
+	}
+	NotLikeOperatorRights []INotLikeOperatorRight
+	INotLikeOperatorRight interface {
+		iNotLikeOperatorRight()
+		Right() Right
+	}
+	NotLikeOperator struct {
+		RightsAccessor NotLikeOperatorRights
+		Policy         LikePolicy
+	}
+)
+
+func (NotLikeOperator) ToString() string     { return "not like" }
+func (NotLikeOperator) iComparisonOperator() {}
+func (eo NotLikeOperator) Rights() Rights {
+	return lo.Map(eo.RightsAccessor, func(item INotLikeOperatorRight, index int) Right {
+		return item.Right()
+	})
+}
+func (eo NotLikeOperator) validComparison(node *sqlparser.ComparisonExpr) bool {
+	return eo.Policy.valid(node)
+}
+
+// LikePolicy restricts the patterns accepted by LikeOperator and
+// NotLikeOperator, so that an allowed pattern can't become a full table scan.
+type LikePolicy struct {
+	// LeadingWildcard allows patterns that start with % or _.
+	LeadingWildcard bool
+	// MaxWildcards limits the number of unescaped % and _ in the pattern.
+	MaxWildcards int
+	// Escapes lists the characters allowed in an ESCAPE clause, so when it is
+	// empty no ESCAPE clause is allowed. Without one, \ is the escape character.
+	Escapes []string
+	// ValidationFunc, if set, is called with the pattern as written.
+	ValidationFunc func(string) bool
+}
+
+func (lp LikePolicy) valid(node *sqlparser.ComparisonExpr) bool {
+	pattern, ok := node.Right.(*sqlparser.Literal)
+	if !ok || pattern.Type != sqlparser.StrVal {
+		return false
+	}
+
+	escape := "\\"
+	if node.Escape != nil {
+		literal, ok := node.Escape.(*sqlparser.Literal)
+		if !ok || literal.Type != sqlparser.StrVal || !lo.Contains(lp.Escapes, literal.Val) {
+			return false
+		}
+		escape = literal.Val
+	}
+
+	wildcards, leading := likeWildcards(pattern.Val, escape)
+	if leading && !lp.LeadingWildcard {
+		return false
+	}
+	if lp.MaxWildcards != UNLIMITED && wildcards > lp.MaxWildcards {
+		return false
+	}
+
+	return lp.ValidationFunc == nil || lp.ValidationFunc(pattern.Val)
+}
+
+// likeWildcards counts the unescaped wildcards in pattern and reports
+// whether the pattern starts with one.
+func likeWildcards(pattern string, escape string) (int, bool) {
+	runes := []rune(pattern)
+	escapeRunes := []rune(escape)
+	wildcards := 0
+	leading := false
+
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case len(escapeRunes) == 1 && runes[i] == escapeRunes[0]:
+			i++
+		case runes[i] == '%' || runes[i] == '_':
+			wildcards++
+			leading = leading || i == 0
+		}
+	}
+
+	return wildcards, leading
+}
+
 // BetweenOperator
 type (
 	BetweenOperatorFroms []IBetweenOperatorFrom
@@ -269,6 +470,12 @@ type (
 		FromsAccessor BetweenOperatorFroms
 		TosAccessor   BetweenOperatorTos
 	}
+	// PairedBetweenOperator is a BetweenOperator that also checks the from
+	// and to values together, e.g. that from is not after to.
+	PairedBetweenOperator struct {
+		BetweenOperator
+		ValidationFunc func(from, to sqlparser.Expr) bool
+	}
 )
 
 func (BetweenOperator) ToString() string  { return "between" }
@@ -283,6 +490,74 @@ func (bo BetweenOperator) Tos() Tos {
 		return item.To()
 	})
 }
+func (pbo PairedBetweenOperator) validBetween(node *sqlparser.BetweenExpr) bool {
+	return pbo.ValidationFunc(node.From, node.To)
+}
+func (pbo PairedBetweenOperator) missingFunc() bool { return pbo.ValidationFunc == nil }
+
+// NotBetweenOperator
+type (
+	NotBetweenOperatorFroms []INotBetweenOperatorFrom
+	NotBetweenOperatorTos   []INotBetweenOperatorTo
+	INotBetweenOperatorFrom interface {
+		iNotBetweenOperatorFrom()
+		From() From
+	}
+	INotBetweenOperatorTo interface {
+		iNotBetweenOperatorTo()
+		To() To
+	}
+	NotBetweenOperator struct {
+		FromsAccessor NotBetweenOperatorFroms
+		TosAccessor   NotBetweenOperatorTos
+	}
+	// PairedNotBetweenOperator is a NotBetweenOperator that also checks the
+	// from and to values together.
+	PairedNotBetweenOperator struct {
+		NotBetweenOperator
+		ValidationFunc func(from, to sqlparser.Expr) bool
+	}
+)
+
+func (NotBetweenOperator) ToString() string     { return "not between" }
+func (NotBetweenOperator) iNotBetweenOperator() {}
+func (nbo NotBetweenOperator) Froms() Froms {
+	return lo.Map(nbo.FromsAccessor, func(item INotBetweenOperatorFrom, index int) From {
+		return item.From()
+	})
+}
+func (nbo NotBetweenOperator) Tos() Tos {
+	return lo.Map(nbo.TosAccessor, func(item INotBetweenOperatorTo, index int) To {
+		return item.To()
+	})
+}
+func (pnbo PairedNotBetweenOperator) validBetween(node *sqlparser.BetweenExpr) bool {
+	return pnbo.ValidationFunc(node.From, node.To)
+}
+func (pnbo PairedNotBetweenOperator) missingFunc() bool { return pnbo.ValidationFunc == nil }
+
+// IsOperators
+type (
+	IsNullOperator     struct{}
+	IsNotNullOperator  struct{}
+	IsTrueOperator     struct{}
+	IsNotTrueOperator  struct{}
+	IsFalseOperator    struct{}
+	IsNotFalseOperator struct{}
+)
+
+func (IsNullOperator) ToString() string     { return sqlparser.IsNullOp.ToString() }
+func (IsNullOperator) iIsOperator()         {}
+func (IsNotNullOperator) ToString() string  { return sqlparser.IsNotNullOp.ToString() }
+func (IsNotNullOperator) iIsOperator()      {}
+func (IsTrueOperator) ToString() string     { return sqlparser.IsTrueOp.ToString() }
+func (IsTrueOperator) iIsOperator()         {}
+func (IsNotTrueOperator) ToString() string  { return sqlparser.IsNotTrueOp.ToString() }
+func (IsNotTrueOperator) iIsOperator()      {}
+func (IsFalseOperator) ToString() string    { return sqlparser.IsFalseOp.ToString() }
+func (IsFalseOperator) iIsOperator()        {}
+func (IsNotFalseOperator) ToString() string { return sqlparser.IsNotFalseOp.ToString() }
+func (IsNotFalseOperator) iIsOperator()     {}
 
 // LiteralValueType
 type (
@@ -301,8 +576,12 @@ func (LiteralValue) iGreaterThanOperatorRight()        {}
 func (LiteralValue) iLessThanOperatorRight()           {}
 func (LiteralValue) iGreaterThanOrEqualOperatorRight() {}
 func (LiteralValue) iLessThanOrEqualOperatorRight()    {}
+func (LiteralValue) iLikeOperatorRight()               {}
+func (LiteralValue) iNotLikeOperatorRight()            {}
 func (LiteralValue) iBetweenOperatorFrom()             {}
 func (LiteralValue) iBetweenOperatorTo()               {}
+func (LiteralValue) iNotBetweenOperatorFrom()          {}
+func (LiteralValue) iNotBetweenOperatorTo()            {}
 func (LiteralValue) iRight()                           {}
 func (lv LiteralValue) Right() Right                   { return lv }
 func (LiteralValue) iFrom()                            {}
@@ -310,7 +589,15 @@ func (lv LiteralValue) From() From                     { return lv }
 func (LiteralValue) iTo()                              {}
 func (lv LiteralValue) To() To                         { return lv }
 func (lv LiteralValue) valid(e any) bool               { return lv.ValueType.valid(e) }
-func (LiteralValue) nodeType() string                  { return "*sqlparser.Literal" }
+func (lv LiteralValue) outOfRange(e any) bool          { return isOutOfRange(lv.ValueType, e) }
+func (lv LiteralValue) matchesNodeType(t string) bool {
+	if matcher, ok := lv.ValueType.(nodeTypeMatcher); ok {
+		return matcher.matchesNodeType(t)
+	}
+	return t == lv.nodeType()
+}
+func (lv LiteralValue) canonical(e sqlparser.Expr) sqlparser.Expr { return canonical(lv.ValueType, e) }
+func (LiteralValue) nodeType() string                             { return "*sqlparser.Literal" }
 
 // StringValue
 type StringValue struct {
@@ -324,7 +611,8 @@ func (sv StringValue) valid(s any) bool {
 	parent, ok := s.(*sqlparser.Literal)
 	return ok && parent.Type == sqlparser.StrVal && sv.ValidationFunc(parent.Val)
 }
-func (StringValue) nodeType() string { return LiteralValue{}.nodeType() }
+func (StringValue) nodeType() string     { return LiteralValue{}.nodeType() }
+func (sv StringValue) missingFunc() bool { return sv.ValidationFunc == nil }
 
 // StringValues
 type StringValues struct {
@@ -357,7 +645,8 @@ func (sv StringValues) valid(s any) bool {
 		return false
 	}
 }
-func (StringValues) nodeType() string { return TupleValue{}.nodeType() }
+func (StringValues) nodeType() string     { return TupleValue{}.nodeType() }
+func (sv StringValues) missingFunc() bool { return sv.ValidationFunc == nil }
 
 // IntegerValue
 type IntegerValue struct {
@@ -368,8 +657,13 @@ func (IntegerValue) iLiteralValueType() {}
 func (IntegerValue) iRight()            {}
 func (iv IntegerValue) Right() Right    { return iv }
 func (iv IntegerValue) valid(s any) bool {
-	parent, ok := s.(*sqlparser.Literal)
-	return ok && parent.Type == sqlparser.IntVal && iv.ValidationFunc(cast.ToInt(parent.Val))
+	value, err := intValue(s)
+	return err == nil && iv.ValidationFunc(value)
+}
+func (iv IntegerValue) missingFunc() bool { return iv.ValidationFunc == nil }
+func (IntegerValue) outOfRange(s any) bool {
+	_, err := intValue(s)
+	return errors.Is(err, strconv.ErrRange)
 }
 func (IntegerValue) nodeType() string { return LiteralValue{}.nodeType() }
 
@@ -382,29 +676,792 @@ func (IntegerValues) iTupleValueType() {}
 func (IntegerValues) iRight()          {}
 func (iv IntegerValues) Right() Right  { return iv }
 func (iv IntegerValues) valid(s any) bool {
+	values, ok := tupleValues(s, ignoreError(intValue))
+	return ok && iv.ValidationFunc(values)
+}
+func (IntegerValues) outOfRange(s any) bool { return tupleOutOfRange(s, intValue) }
+func (IntegerValues) nodeType() string      { return TupleValue{}.nodeType() }
+func (iv IntegerValues) missingFunc() bool  { return iv.ValidationFunc == nil }
+
+// Int64Value
+type Int64Value struct {
+	ValidationFunc func(int64) bool
+}
+
+func (Int64Value) iLiteralValueType() {}
+func (Int64Value) iRight()            {}
+func (iv Int64Value) Right() Right    { return iv }
+func (iv Int64Value) valid(s any) bool {
+	value, err := int64Value(s)
+	return err == nil && iv.ValidationFunc(value)
+}
+func (iv Int64Value) missingFunc() bool { return iv.ValidationFunc == nil }
+func (Int64Value) outOfRange(s any) bool {
+	_, err := int64Value(s)
+	return errors.Is(err, strconv.ErrRange)
+}
+func (Int64Value) nodeType() string { return LiteralValue{}.nodeType() }
+
+// Int64Values
+type Int64Values struct {
+	ValidationFunc func([]int64) bool
+}
+
+func (Int64Values) iTupleValueType() {}
+func (Int64Values) iRight()          {}
+func (iv Int64Values) Right() Right  { return iv }
+func (iv Int64Values) valid(s any) bool {
+	values, ok := tupleValues(s, ignoreError(int64Value))
+	return ok && iv.ValidationFunc(values)
+}
+func (Int64Values) outOfRange(s any) bool { return tupleOutOfRange(s, int64Value) }
+func (Int64Values) nodeType() string      { return TupleValue{}.nodeType() }
+func (iv Int64Values) missingFunc() bool  { return iv.ValidationFunc == nil }
+
+// Uint64Value
+type Uint64Value struct {
+	ValidationFunc func(uint64) bool
+}
+
+func (Uint64Value) iLiteralValueType() {}
+func (Uint64Value) iRight()            {}
+func (uv Uint64Value) Right() Right    { return uv }
+func (uv Uint64Value) valid(s any) bool {
+	value, err := uint64Value(s)
+	return err == nil && uv.ValidationFunc(value)
+}
+func (uv Uint64Value) missingFunc() bool { return uv.ValidationFunc == nil }
+func (Uint64Value) outOfRange(s any) bool {
+	_, err := uint64Value(s)
+	return errors.Is(err, strconv.ErrRange)
+}
+func (Uint64Value) nodeType() string { return LiteralValue{}.nodeType() }
+
+// Uint64Values
+type Uint64Values struct {
+	ValidationFunc func([]uint64) bool
+}
+
+func (Uint64Values) iTupleValueType() {}
+func (Uint64Values) iRight()          {}
+func (uv Uint64Values) Right() Right  { return uv }
+func (uv Uint64Values) valid(s any) bool {
+	values, ok := tupleValues(s, ignoreError(uint64Value))
+	return ok && uv.ValidationFunc(values)
+}
+func (Uint64Values) outOfRange(s any) bool { return tupleOutOfRange(s, uint64Value) }
+func (Uint64Values) nodeType() string      { return TupleValue{}.nodeType() }
+func (uv Uint64Values) missingFunc() bool  { return uv.ValidationFunc == nil }
+
+func isOutOfRange(value any, s any) bool {
+	checker, ok := value.(rangeChecker)
+	return ok && checker.outOfRange(s)
+}
+
+var errNotInteger = errors.New("not an integer literal")
+
+func integerLiteral(s any) (string, error) {
+	parent, ok := s.(*sqlparser.Literal)
+	if !ok || parent.Type != sqlparser.IntVal {
+		return "", errNotInteger
+	}
+
+	return parent.Val, nil
+}
+
+func intValue(s any) (int, error) {
+	val, err := integerLiteral(s)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(val, 10, strconv.IntSize)
+	return int(value), err
+}
+
+func int64Value(s any) (int64, error) {
+	val, err := integerLiteral(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(val, 10, 64)
+}
+
+func uint64Value(s any) (uint64, error) {
+	val, err := integerLiteral(s)
+	if err != nil {
+		return 0, err
+	}
+
+	// ParseUint reports a syntax error for a sign, but a negative number is
+	// really just below the range.
+	if strings.HasPrefix(val, "-") {
+		return 0, strconv.ErrRange
+	}
+
+	return strconv.ParseUint(val, 10, 64)
+}
+
+func ignoreError[T any](convert func(any) (T, error)) func(any) (T, bool) {
+	return func(s any) (T, bool) {
+		value, err := convert(s)
+		return value, err == nil
+	}
+}
+
+func tupleOutOfRange[T any](s any, convert func(any) (T, error)) bool {
 	parent, ok := s.(sqlparser.ValTuple)
 
-	if ok {
-		values := lo.FilterMap(parent, func(item sqlparser.Expr, index int) (int, bool) {
-			value, ok := item.(*sqlparser.Literal)
+	return ok && lo.ContainsBy(parent, func(item sqlparser.Expr) bool {
+		_, err := convert(item)
+		return errors.Is(err, strconv.ErrRange)
+	})
+}
 
-			if ok && value.Type == sqlparser.IntVal {
-				return cast.ToInt(value.Val), true
-			} else {
-				return 0, false
-			}
-		})
+// FloatValue
+type FloatValue struct {
+	ValidationFunc func(float64) bool
+}
 
-		if len(values) == len(parent) && iv.ValidationFunc(values) {
-			return true
-		} else {
-			return false
+func (FloatValue) iLiteralValueType() {}
+func (FloatValue) iRight()            {}
+func (fv FloatValue) Right() Right    { return fv }
+func (fv FloatValue) valid(s any) bool {
+	value, ok := floatValue(s)
+	return ok && fv.ValidationFunc(value)
+}
+func (FloatValue) nodeType() string     { return LiteralValue{}.nodeType() }
+func (fv FloatValue) missingFunc() bool { return fv.ValidationFunc == nil }
+
+// FloatValues
+type FloatValues struct {
+	ValidationFunc func([]float64) bool
+}
+
+func (FloatValues) iTupleValueType() {}
+func (FloatValues) iRight()          {}
+func (fv FloatValues) Right() Right  { return fv }
+func (fv FloatValues) valid(s any) bool {
+	values, ok := tupleValues(s, floatValue)
+	return ok && fv.ValidationFunc(values)
+}
+func (FloatValues) nodeType() string     { return TupleValue{}.nodeType() }
+func (fv FloatValues) missingFunc() bool { return fv.ValidationFunc == nil }
+
+func floatValue(s any) (float64, bool) {
+	parent, ok := s.(*sqlparser.Literal)
+	if !ok || !lo.Contains([]sqlparser.ValType{sqlparser.IntVal, sqlparser.DecimalVal, sqlparser.FloatVal}, parent.Type) {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(parent.Val, 64)
+	return value, err == nil
+}
+
+// DecimalValue accepts integer and decimal literals that fit within
+// MaxPrecision and MaxScale, either of which may be UNLIMITED.
+type DecimalValue struct {
+	MaxPrecision   int
+	MaxScale       int
+	ValidationFunc func(Decimal) bool
+}
+
+func (DecimalValue) iLiteralValueType() {}
+func (DecimalValue) iRight()            {}
+func (dv DecimalValue) Right() Right    { return dv }
+func (dv DecimalValue) valid(s any) bool {
+	value, ok := decimalValue(dv.MaxPrecision, dv.MaxScale)(s)
+	return ok && dv.ValidationFunc(value)
+}
+func (DecimalValue) nodeType() string     { return LiteralValue{}.nodeType() }
+func (dv DecimalValue) missingFunc() bool { return dv.ValidationFunc == nil }
+
+// DecimalValues
+type DecimalValues struct {
+	MaxPrecision   int
+	MaxScale       int
+	ValidationFunc func([]Decimal) bool
+}
+
+func (DecimalValues) iTupleValueType() {}
+func (DecimalValues) iRight()          {}
+func (dv DecimalValues) Right() Right  { return dv }
+func (dv DecimalValues) valid(s any) bool {
+	values, ok := tupleValues(s, decimalValue(dv.MaxPrecision, dv.MaxScale))
+	return ok && dv.ValidationFunc(values)
+}
+func (DecimalValues) nodeType() string     { return TupleValue{}.nodeType() }
+func (dv DecimalValues) missingFunc() bool { return dv.ValidationFunc == nil }
+
+func decimalValue(maxPrecision int, maxScale int) func(any) (Decimal, bool) {
+	return func(s any) (Decimal, bool) {
+		parent, ok := s.(*sqlparser.Literal)
+		if !ok || (parent.Type != sqlparser.IntVal && parent.Type != sqlparser.DecimalVal) {
+			return Decimal{}, false
 		}
-	} else {
-		return false
+
+		value, err := ParseDecimal(parent.Val)
+		if err != nil ||
+			(maxPrecision != UNLIMITED && value.Precision() > maxPrecision) ||
+			(maxScale != UNLIMITED && value.Scale > maxScale) {
+			return Decimal{}, false
+		}
+
+		return value, true
+	}
+}
+
+// DateValue accepts dates written as strings in one of Layouts, by default
+// 2006-01-02, or as DATE '...' literals. Min and Max bound the date when set,
+// and MaxAge and MaxAhead bound it relative to now when positive. The date is
+// rendered as 2006-01-02. ValidationFunc is optional.
+type DateValue struct {
+	Layouts        []string
+	Min            time.Time
+	Max            time.Time
+	MaxAge         time.Duration
+	MaxAhead       time.Duration
+	ValidationFunc func(time.Time) bool
+}
+
+func (DateValue) iLiteralValueType() {}
+func (DateValue) iRight()            {}
+func (dv DateValue) Right() Right    { return dv }
+func (dv DateValue) valid(s any) bool {
+	value, ok := dv.spec().value(s)
+	return ok && (dv.ValidationFunc == nil || dv.ValidationFunc(value))
+}
+func (DateValue) nodeType() string                             { return LiteralValue{}.nodeType() }
+func (dv DateValue) canonical(e sqlparser.Expr) sqlparser.Expr { return dv.spec().canonical(e) }
+func (dv DateValue) spec() timeSpec {
+	return timeSpec{
+		types:    []sqlparser.ValType{sqlparser.StrVal, sqlparser.DateVal},
+		layouts:  lo.Ternary(len(dv.Layouts) > 0, dv.Layouts, []string{"2006-01-02"}),
+		output:   "2006-01-02",
+		min:      dv.Min,
+		max:      dv.Max,
+		maxAge:   dv.MaxAge,
+		maxAhead: dv.MaxAhead,
+	}
+}
+
+// DateValues
+type DateValues struct {
+	Layouts        []string
+	Min            time.Time
+	Max            time.Time
+	MaxAge         time.Duration
+	MaxAhead       time.Duration
+	ValidationFunc func([]time.Time) bool
+}
+
+func (DateValues) iTupleValueType() {}
+func (DateValues) iRight()          {}
+func (dv DateValues) Right() Right  { return dv }
+func (dv DateValues) valid(s any) bool {
+	values, ok := tupleValues(s, dv.element().spec().value)
+	return ok && (dv.ValidationFunc == nil || dv.ValidationFunc(values))
+}
+func (DateValues) nodeType() string { return TupleValue{}.nodeType() }
+func (dv DateValues) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalTuple(dv.element(), e)
+}
+func (dv DateValues) element() DateValue {
+	return DateValue{Layouts: dv.Layouts, Min: dv.Min, Max: dv.Max, MaxAge: dv.MaxAge, MaxAhead: dv.MaxAhead}
+}
+
+// DateTimeValue accepts wall clock times written as strings in one of
+// Layouts, by default 2006-01-02 15:04:05 or 2006-01-02T15:04:05, or as
+// TIMESTAMP '...' literals. Times are read in Location, UTC if nil, and with
+// UTC set are rendered converted to UTC. Bounds work as for DateValue.
+type DateTimeValue struct {
+	Layouts        []string
+	Location       *time.Location
+	UTC            bool
+	Min            time.Time
+	Max            time.Time
+	MaxAge         time.Duration
+	MaxAhead       time.Duration
+	ValidationFunc func(time.Time) bool
+}
+
+func (DateTimeValue) iLiteralValueType() {}
+func (DateTimeValue) iRight()            {}
+func (dv DateTimeValue) Right() Right    { return dv }
+func (dv DateTimeValue) valid(s any) bool {
+	value, ok := dv.spec().value(s)
+	return ok && (dv.ValidationFunc == nil || dv.ValidationFunc(value))
+}
+func (DateTimeValue) nodeType() string                             { return LiteralValue{}.nodeType() }
+func (dv DateTimeValue) canonical(e sqlparser.Expr) sqlparser.Expr { return dv.spec().canonical(e) }
+func (dv DateTimeValue) spec() timeSpec {
+	return timeSpec{
+		types:    []sqlparser.ValType{sqlparser.StrVal, sqlparser.TimestampVal},
+		layouts:  lo.Ternary(len(dv.Layouts) > 0, dv.Layouts, []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05"}),
+		output:   "2006-01-02 15:04:05.999999999",
+		location: dv.Location,
+		utc:      dv.UTC,
+		min:      dv.Min,
+		max:      dv.Max,
+		maxAge:   dv.MaxAge,
+		maxAhead: dv.MaxAhead,
+	}
+}
+
+// DateTimeValues
+type DateTimeValues struct {
+	Layouts        []string
+	Location       *time.Location
+	UTC            bool
+	Min            time.Time
+	Max            time.Time
+	MaxAge         time.Duration
+	MaxAhead       time.Duration
+	ValidationFunc func([]time.Time) bool
+}
+
+func (DateTimeValues) iTupleValueType() {}
+func (DateTimeValues) iRight()          {}
+func (dv DateTimeValues) Right() Right  { return dv }
+func (dv DateTimeValues) valid(s any) bool {
+	values, ok := tupleValues(s, dv.element().spec().value)
+	return ok && (dv.ValidationFunc == nil || dv.ValidationFunc(values))
+}
+func (DateTimeValues) nodeType() string { return TupleValue{}.nodeType() }
+func (dv DateTimeValues) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalTuple(dv.element(), e)
+}
+func (dv DateTimeValues) element() DateTimeValue {
+	return DateTimeValue{Layouts: dv.Layouts, Location: dv.Location, UTC: dv.UTC, Min: dv.Min, Max: dv.Max, MaxAge: dv.MaxAge, MaxAhead: dv.MaxAhead}
+}
+
+// TimestampValue accepts instants written as strings in one of Layouts, by
+// default RFC 3339 with either a T or a space, or as TIMESTAMP '...'
+// literals. Times without an offset are read in Location, UTC if nil. They
+// are rendered with their offset if they were written with one, or
+// converted to UTC with UTC set. Bounds work as for DateValue.
+type TimestampValue struct {
+	Layouts        []string
+	Location       *time.Location
+	UTC            bool
+	Min            time.Time
+	Max            time.Time
+	MaxAge         time.Duration
+	MaxAhead       time.Duration
+	ValidationFunc func(time.Time) bool
+}
+
+func (TimestampValue) iLiteralValueType() {}
+func (TimestampValue) iRight()            {}
+func (tv TimestampValue) Right() Right    { return tv }
+func (tv TimestampValue) valid(s any) bool {
+	value, ok := tv.spec().value(s)
+	return ok && (tv.ValidationFunc == nil || tv.ValidationFunc(value))
+}
+func (TimestampValue) nodeType() string                             { return LiteralValue{}.nodeType() }
+func (tv TimestampValue) canonical(e sqlparser.Expr) sqlparser.Expr { return tv.spec().canonical(e) }
+func (tv TimestampValue) spec() timeSpec {
+	return timeSpec{
+		types:    []sqlparser.ValType{sqlparser.StrVal, sqlparser.TimestampVal},
+		layouts:  lo.Ternary(len(tv.Layouts) > 0, tv.Layouts, []string{time.RFC3339, "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05"}),
+		output:   "2006-01-02 15:04:05.999999999",
+		location: tv.Location,
+		utc:      tv.UTC,
+		zoned:    !tv.UTC,
+		min:      tv.Min,
+		max:      tv.Max,
+		maxAge:   tv.MaxAge,
+		maxAhead: tv.MaxAhead,
+	}
+}
+
+// TimestampValues
+type TimestampValues struct {
+	Layouts        []string
+	Location       *time.Location
+	UTC            bool
+	Min            time.Time
+	Max            time.Time
+	MaxAge         time.Duration
+	MaxAhead       time.Duration
+	ValidationFunc func([]time.Time) bool
+}
+
+func (TimestampValues) iTupleValueType() {}
+func (TimestampValues) iRight()          {}
+func (tv TimestampValues) Right() Right  { return tv }
+func (tv TimestampValues) valid(s any) bool {
+	values, ok := tupleValues(s, tv.element().spec().value)
+	return ok && (tv.ValidationFunc == nil || tv.ValidationFunc(values))
+}
+func (TimestampValues) nodeType() string { return TupleValue{}.nodeType() }
+func (tv TimestampValues) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalTuple(tv.element(), e)
+}
+func (tv TimestampValues) element() TimestampValue {
+	return TimestampValue{Layouts: tv.Layouts, Location: tv.Location, UTC: tv.UTC, Min: tv.Min, Max: tv.Max, MaxAge: tv.MaxAge, MaxAhead: tv.MaxAhead}
+}
+
+// timeSpec holds what the date and time value types need to read, bound
+// and render a time.
+type timeSpec struct {
+	types    []sqlparser.ValType
+	layouts  []string
+	output   string
+	location *time.Location
+	utc      bool
+	// zoned keeps the offset of times written with one.
+	zoned    bool
+	min      time.Time
+	max      time.Time
+	maxAge   time.Duration
+	maxAhead time.Duration
+}
+
+func (ts timeSpec) parse(s any) (time.Time, bool) {
+	value, _, ok := ts.parseLayout(s)
+	return value, ok
+}
+
+// parseLayout also returns the layout the time was written in.
+func (ts timeSpec) parseLayout(s any) (time.Time, string, bool) {
+	parent, ok := s.(*sqlparser.Literal)
+	if !ok || !lo.Contains(ts.types, parent.Type) {
+		return time.Time{}, "", false
+	}
+
+	location := lo.Ternary(ts.location != nil, ts.location, time.UTC)
+	for _, layout := range ts.layouts {
+		if value, err := time.ParseInLocation(layout, parent.Val, location); err == nil {
+			return value, layout, true
+		}
+	}
+
+	return time.Time{}, "", false
+}
+
+func (ts timeSpec) value(s any) (time.Time, bool) {
+	value, ok := ts.parse(s)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	switch {
+	case !ts.min.IsZero() && value.Before(ts.min),
+		!ts.max.IsZero() && value.After(ts.max),
+		ts.maxAge > 0 && value.Before(now.Add(-ts.maxAge)),
+		ts.maxAhead > 0 && value.After(now.Add(ts.maxAhead)):
+		return time.Time{}, false
+	}
+
+	return value, true
+}
+
+func (ts timeSpec) canonical(e sqlparser.Expr) sqlparser.Expr {
+	value, layout, ok := ts.parseLayout(e)
+	if !ok {
+		return e
+	}
+
+	if ts.utc {
+		value = value.UTC()
+	}
+
+	// A TIMESTAMP literal would drop the offset, so zoned times stay strings.
+	// Zone elements are the only ones in a layout with 07 or MST.
+	if ts.zoned && (strings.Contains(layout, "07") || strings.Contains(layout, "MST")) {
+		return &sqlparser.Literal{Type: sqlparser.StrVal, Val: value.Format(ts.output + "-07:00")}
+	}
+	return &sqlparser.Literal{Type: e.(*sqlparser.Literal).Type, Val: value.Format(ts.output)}
+}
+
+func canonicalTuple(value any, e sqlparser.Expr) sqlparser.Expr {
+	if tuple, ok := e.(sqlparser.ValTuple); ok {
+		for i, item := range tuple {
+			tuple[i] = canonical(value, item)
+		}
+	}
+	return e
+}
+
+// UUIDValue accepts UUID strings, with or without hyphens and in any case,
+// and renders them lowercased and hyphenated. ValidationFunc is optional and
+// receives the canonical form.
+type UUIDValue struct {
+	ValidationFunc func(string) bool
+}
+
+func (UUIDValue) iLiteralValueType() {}
+func (UUIDValue) iRight()            {}
+func (uv UUIDValue) Right() Right    { return uv }
+func (uv UUIDValue) valid(s any) bool {
+	value, ok := stringLiteral(parseUUID)(s)
+	return ok && (uv.ValidationFunc == nil || uv.ValidationFunc(value))
+}
+func (UUIDValue) nodeType() string { return LiteralValue{}.nodeType() }
+func (UUIDValue) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalString(e, parseUUID, func(value string) string { return value })
+}
+
+// UUIDValues
+type UUIDValues struct {
+	ValidationFunc func([]string) bool
+}
+
+func (UUIDValues) iTupleValueType() {}
+func (UUIDValues) iRight()          {}
+func (uv UUIDValues) Right() Right  { return uv }
+func (uv UUIDValues) valid(s any) bool {
+	values, ok := tupleValues(s, stringLiteral(parseUUID))
+	return ok && (uv.ValidationFunc == nil || uv.ValidationFunc(values))
+}
+func (UUIDValues) nodeType() string                          { return TupleValue{}.nodeType() }
+func (UUIDValues) canonical(e sqlparser.Expr) sqlparser.Expr { return canonicalTuple(UUIDValue{}, e) }
+
+// IPValue accepts IPv4 and IPv6 address strings without a zone, and renders
+// them in their normalized form, e.g. 2001:db8::1. ValidationFunc is
+// optional.
+type IPValue struct {
+	ValidationFunc func(netip.Addr) bool
+}
+
+func (IPValue) iLiteralValueType() {}
+func (IPValue) iRight()            {}
+func (iv IPValue) Right() Right    { return iv }
+func (iv IPValue) valid(s any) bool {
+	value, ok := stringLiteral(parseIP)(s)
+	return ok && (iv.ValidationFunc == nil || iv.ValidationFunc(value))
+}
+func (IPValue) nodeType() string { return LiteralValue{}.nodeType() }
+func (IPValue) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalString(e, parseIP, netip.Addr.String)
+}
+
+// IPValues
+type IPValues struct {
+	ValidationFunc func([]netip.Addr) bool
+}
+
+func (IPValues) iTupleValueType() {}
+func (IPValues) iRight()          {}
+func (iv IPValues) Right() Right  { return iv }
+func (iv IPValues) valid(s any) bool {
+	values, ok := tupleValues(s, stringLiteral(parseIP))
+	return ok && (iv.ValidationFunc == nil || iv.ValidationFunc(values))
+}
+func (IPValues) nodeType() string                          { return TupleValue{}.nodeType() }
+func (IPValues) canonical(e sqlparser.Expr) sqlparser.Expr { return canonicalTuple(IPValue{}, e) }
+
+// CIDRValue accepts IPv4 and IPv6 CIDR range strings, and renders them
+// normalized with any host bits cleared, e.g. 10.1.2.3/8 as 10.0.0.0/8.
+// ValidationFunc is optional and receives the masked prefix.
+type CIDRValue struct {
+	ValidationFunc func(netip.Prefix) bool
+}
+
+func (CIDRValue) iLiteralValueType() {}
+func (CIDRValue) iRight()            {}
+func (cv CIDRValue) Right() Right    { return cv }
+func (cv CIDRValue) valid(s any) bool {
+	value, ok := stringLiteral(parseCIDR)(s)
+	return ok && (cv.ValidationFunc == nil || cv.ValidationFunc(value))
+}
+func (CIDRValue) nodeType() string { return LiteralValue{}.nodeType() }
+func (CIDRValue) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalString(e, parseCIDR, netip.Prefix.String)
+}
+
+// CIDRValues
+type CIDRValues struct {
+	ValidationFunc func([]netip.Prefix) bool
+}
+
+func (CIDRValues) iTupleValueType() {}
+func (CIDRValues) iRight()          {}
+func (cv CIDRValues) Right() Right  { return cv }
+func (cv CIDRValues) valid(s any) bool {
+	values, ok := tupleValues(s, stringLiteral(parseCIDR))
+	return ok && (cv.ValidationFunc == nil || cv.ValidationFunc(values))
+}
+func (CIDRValues) nodeType() string                          { return TupleValue{}.nodeType() }
+func (CIDRValues) canonical(e sqlparser.Expr) sqlparser.Expr { return canonicalTuple(CIDRValue{}, e) }
+
+// EmailValue accepts bare email addresses, without a display name, and
+// renders them with the domain lowercased. ValidationFunc is optional and
+// receives the canonical form.
+type EmailValue struct {
+	ValidationFunc func(string) bool
+}
+
+func (EmailValue) iLiteralValueType() {}
+func (EmailValue) iRight()            {}
+func (ev EmailValue) Right() Right    { return ev }
+func (ev EmailValue) valid(s any) bool {
+	value, ok := stringLiteral(parseEmail)(s)
+	return ok && (ev.ValidationFunc == nil || ev.ValidationFunc(value))
+}
+func (EmailValue) nodeType() string { return LiteralValue{}.nodeType() }
+func (EmailValue) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalString(e, parseEmail, func(value string) string { return value })
+}
+
+// EmailValues
+type EmailValues struct {
+	ValidationFunc func([]string) bool
+}
+
+func (EmailValues) iTupleValueType() {}
+func (EmailValues) iRight()          {}
+func (ev EmailValues) Right() Right  { return ev }
+func (ev EmailValues) valid(s any) bool {
+	values, ok := tupleValues(s, stringLiteral(parseEmail))
+	return ok && (ev.ValidationFunc == nil || ev.ValidationFunc(values))
+}
+func (EmailValues) nodeType() string                          { return TupleValue{}.nodeType() }
+func (EmailValues) canonical(e sqlparser.Expr) sqlparser.Expr { return canonicalTuple(EmailValue{}, e) }
+
+func parseUUID(val string) (string, bool) {
+	digits := strings.ToLower(val)
+	if len(digits) == 36 {
+		if digits[8] != '-' || digits[13] != '-' || digits[18] != '-' || digits[23] != '-' {
+			return "", false
+		}
+		digits = strings.ReplaceAll(digits, "-", "")
+	}
+
+	if len(digits) != 32 || strings.Trim(digits, "0123456789abcdef") != "" {
+		return "", false
+	}
+
+	return digits[0:8] + "-" + digits[8:12] + "-" + digits[12:16] + "-" + digits[16:20] + "-" + digits[20:], true
+}
+
+func parseIP(val string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(val)
+	return addr, err == nil && addr.Zone() == ""
+}
+
+func parseCIDR(val string) (netip.Prefix, bool) {
+	prefix, err := netip.ParsePrefix(val)
+	return prefix.Masked(), err == nil
+}
+
+func parseEmail(val string) (string, bool) {
+	addr, err := mail.ParseAddress(val)
+	if err != nil || addr.Name != "" || addr.Address != val {
+		return "", false
+	}
+
+	local, domain, _ := strings.Cut(addr.Address, "@")
+	return local + "@" + strings.ToLower(domain), true
+}
+
+// stringLiteral reads a string literal with parse.
+func stringLiteral[T any](parse func(string) (T, bool)) func(any) (T, bool) {
+	return func(s any) (T, bool) {
+		parent, ok := s.(*sqlparser.Literal)
+		if !ok || parent.Type != sqlparser.StrVal {
+			var zero T
+			return zero, false
+		}
+
+		return parse(parent.Val)
+	}
+}
+
+func canonicalString[T any](e sqlparser.Expr, parse func(string) (T, bool), format func(T) string) sqlparser.Expr {
+	if value, ok := stringLiteral(parse)(e); ok {
+		return sqlparser.NewStrLiteral(format(value))
+	}
+	return e
+}
+
+// BooleanValue accepts true and false, and with AllowIntegers also 1 and 0,
+// which are rendered as the booleans they stand for.
+type BooleanValue struct {
+	AllowIntegers  bool
+	ValidationFunc func(bool) bool
+}
+
+func (BooleanValue) iLiteralValueType() {}
+func (BooleanValue) iRight()            {}
+func (bv BooleanValue) Right() Right    { return bv }
+func (bv BooleanValue) valid(s any) bool {
+	value, ok := booleanValue(s, bv.AllowIntegers)
+	return ok && bv.ValidationFunc(value)
+}
+func (BooleanValue) nodeType() string     { return "sqlparser.BoolVal" }
+func (bv BooleanValue) missingFunc() bool { return bv.ValidationFunc == nil }
+func (bv BooleanValue) matchesNodeType(t string) bool {
+	return t == bv.nodeType() || (bv.AllowIntegers && t == LiteralValue{}.nodeType())
+}
+func (bv BooleanValue) canonical(e sqlparser.Expr) sqlparser.Expr {
+	if value, ok := booleanValue(e, bv.AllowIntegers); ok {
+		return sqlparser.BoolVal(value)
+	}
+	return e
+}
+
+// BooleanValues
+type BooleanValues struct {
+	AllowIntegers  bool
+	ValidationFunc func([]bool) bool
+}
+
+func (BooleanValues) iTupleValueType() {}
+func (BooleanValues) iRight()          {}
+func (bv BooleanValues) Right() Right  { return bv }
+func (bv BooleanValues) valid(s any) bool {
+	values, ok := tupleValues(s, func(item any) (bool, bool) { return booleanValue(item, bv.AllowIntegers) })
+	return ok && bv.ValidationFunc(values)
+}
+func (BooleanValues) nodeType() string     { return TupleValue{}.nodeType() }
+func (bv BooleanValues) missingFunc() bool { return bv.ValidationFunc == nil }
+func (bv BooleanValues) canonical(e sqlparser.Expr) sqlparser.Expr {
+	return canonicalTuple(BooleanValue{AllowIntegers: bv.AllowIntegers}, e)
+}
+
+func booleanValue(s any, allowIntegers bool) (bool, bool) {
+	switch value := s.(type) {
+	case sqlparser.BoolVal:
+		return bool(value), true
+	case *sqlparser.Literal:
+		if allowIntegers && value.Type == sqlparser.IntVal && (value.Val == "0" || value.Val == "1") {
+			return value.Val == "1", true
+		}
+	}
+	return false, false
+}
+
+func matchesNodeType(value interface{ nodeType() string }, e sqlparser.Expr) bool {
+	if matcher, ok := value.(nodeTypeMatcher); ok {
+		return matcher.matchesNodeType(fmt.Sprintf("%T", e))
 	}
+	return fmt.Sprintf("%T", e) == value.nodeType()
+}
+
+func canonical(value any, e sqlparser.Expr) sqlparser.Expr {
+	if canonicalizer, ok := value.(canonicalizer); ok {
+		return canonicalizer.canonical(e)
+	}
+	return e
+}
+
+// tupleValues converts every item of a tuple, failing if any item can't be.
+func tupleValues[T any](s any, convert func(any) (T, bool)) ([]T, bool) {
+	parent, ok := s.(sqlparser.ValTuple)
+	if !ok {
+		return nil, false
+	}
+
+	values := lo.FilterMap(parent, func(item sqlparser.Expr, index int) (T, bool) {
+		return convert(item)
+	})
+
+	return values, len(values) == len(parent)
 }
-func (IntegerValues) nodeType() string { return TupleValue{}.nodeType() }
 
 // TupleValueType
 type (
@@ -417,9 +1474,11 @@ type (
 	}
 )
 
-func (TupleValue) iInOperatorRight()    {}
-func (TupleValue) iNotInOperatorRight() {}
-func (TupleValue) iRight()              {}
-func (tv TupleValue) Right() Right      { return tv }
-func (tv TupleValue) valid(e any) bool  { return tv.ValueType.valid(e) }
-func (TupleValue) nodeType() string     { return "sqlparser.ValTuple" }
+func (TupleValue) iInOperatorRight()                            {}
+func (TupleValue) iNotInOperatorRight()                         {}
+func (TupleValue) iRight()                                      {}
+func (tv TupleValue) Right() Right                              { return tv }
+func (tv TupleValue) valid(e any) bool                          { return tv.ValueType.valid(e) }
+func (tv TupleValue) outOfRange(e any) bool                     { return isOutOfRange(tv.ValueType, e) }
+func (tv TupleValue) canonical(e sqlparser.Expr) sqlparser.Expr { return canonical(tv.ValueType, e) }
+func (TupleValue) nodeType() string                             { return "sqlparser.ValTuple" }
@@ -0,0 +1,243 @@
+package filtersql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Validate reports the mistakes in a Config that would otherwise only show
+// up once a matching filter arrives: negative limits other than UNLIMITED,
+// columns without a name, with an invalid Target or declared twice, and so
+// shadowed, nil operators and values, required ValidationFuncs left nil, and
+// operators whose values can never match, such as one without any values or
+// in with a scalar value. All problems are returned, joined with
+// errors.Join, or nil if there are none.
+func (config Config) Validate() error {
+	errs := []error{}
+
+	limits := []lo.Tuple2[string, int]{
+		{A: "Ors", B: config.Allow.Ors},
+		{A: "Ands", B: config.Allow.Ands},
+		{A: "Nots", B: config.Allow.Nots},
+		{A: "GroupingParens", B: config.Allow.GroupingParens},
+		{A: "TupleParens", B: config.Allow.TupleParens},
+		{A: "MaxDepth", B: config.Allow.MaxDepth},
+		{A: "MaxPredicates", B: config.Allow.MaxPredicates},
+	}
+	for _, limit := range limits {
+		if limit.B < UNLIMITED {
+			errs = append(errs, fmt.Errorf("invalid limit %s: %d", limit.A, limit.B))
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, left := range config.Allow.Comparisons {
+		var column Column
+		switch left := left.(type) {
+		case Column:
+			column = left
+		case TypedColumn:
+			column = left.Column()
+		default:
+			errs = append(errs, fmt.Errorf("unsupported comparison: %T", left))
+			continue
+		}
+
+		name := qualifiedName(column)
+		if column.Name == "" {
+			errs = append(errs, errors.New("column without a name"))
+		}
+
+		if column.Target != "" {
+			if _, err := column.target(); err != nil {
+				errs = append(errs, fmt.Errorf("column %s: %w", name, err))
+			}
+		}
+
+		key := column.Qualifier + "." + strings.ToLower(column.Name)
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("duplicate column: %s", name))
+		}
+		seen[key] = true
+
+		for _, err := range validateColumn(column) {
+			errs = append(errs, fmt.Errorf("column %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateColumn(column Column) []error {
+	errs := []error{}
+
+	seen := map[string]bool{}
+	for _, op := range column.ComparisonOperators {
+		if op == nil {
+			errs = append(errs, errors.New("nil comparison operator"))
+			continue
+		}
+
+		name := op.ToString()
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("duplicate operator: %s", name))
+		}
+		seen[name] = true
+
+		errs = append(errs, validateOperator(op)...)
+	}
+
+	if column.BetweenOperator != nil {
+		op := column.BetweenOperator
+		errs = append(errs, validateBetween(op.ToString(), op, op.Froms(), op.Tos())...)
+	}
+	if column.NotBetweenOperator != nil {
+		op := column.NotBetweenOperator
+		errs = append(errs, validateBetween(op.ToString(), op, op.Froms(), op.Tos())...)
+	}
+
+	seen = map[string]bool{}
+	for _, op := range column.IsOperators {
+		if op == nil {
+			errs = append(errs, errors.New("nil is operator"))
+		} else if seen[op.ToString()] {
+			errs = append(errs, fmt.Errorf("duplicate operator: %s", op.ToString()))
+		} else {
+			seen[op.ToString()] = true
+		}
+	}
+
+	return errs
+}
+
+func validateOperator(op IComparisonOperator) []error {
+	errs := []error{}
+	name := op.ToString()
+	list := name == "in" || name == "not in"
+	like := name == "like" || name == "not like"
+
+	switch op := op.(type) {
+	case LikeOperator:
+		errs = append(errs, validateLikePolicy(op.Policy)...)
+	case NotLikeOperator:
+		errs = append(errs, validateLikePolicy(op.Policy)...)
+	}
+
+	rights := op.Rights()
+	if len(rights) == 0 {
+		errs = append(errs, fmt.Errorf("operator %s allows no values", name))
+	}
+
+	for _, right := range rights {
+		if err := validateValue(right); err != nil {
+			errs = append(errs, fmt.Errorf("operator %s: %w", name, err))
+			continue
+		}
+
+		// Only in and not in are followed by a tuple, and like patterns are
+		// strings, so these values can never match.
+		if _, custom := right.(CustomValue); custom {
+			continue
+		}
+		tuple := right.nodeType() == TupleValue{}.nodeType()
+		if tuple != list || like && !stringValue(right) {
+			errs = append(errs, fmt.Errorf("operator %s can never match %s", name, valueName(right)))
+		}
+	}
+
+	return errs
+}
+
+func validateBetween(name string, op any, froms Froms, tos Tos) []error {
+	errs := []error{}
+
+	if requirer, ok := op.(funcRequirer); ok && requirer.missingFunc() {
+		errs = append(errs, fmt.Errorf("operator %s: nil ValidationFunc", name))
+	}
+	if len(froms) == 0 || len(tos) == 0 {
+		errs = append(errs, fmt.Errorf("operator %s allows no values", name))
+	}
+
+	for _, from := range froms {
+		if err := validateValue(from); err != nil {
+			errs = append(errs, fmt.Errorf("operator %s: %w", name, err))
+		}
+	}
+	for _, to := range tos {
+		if err := validateValue(to); err != nil {
+			errs = append(errs, fmt.Errorf("operator %s: %w", name, err))
+		}
+	}
+
+	return errs
+}
+
+func validateLikePolicy(policy LikePolicy) []error {
+	if policy.MaxWildcards < UNLIMITED {
+		return []error{fmt.Errorf("invalid limit MaxWildcards: %d", policy.MaxWildcards)}
+	}
+	return nil
+}
+
+// validateValue reports a nil value, value type or required ValidationFunc,
+// any of which would panic once a filter reaches it.
+func validateValue(value any) error {
+	var valueType any = value
+	switch value := value.(type) {
+	case nil:
+		return errors.New("nil value")
+	case LiteralValue:
+		valueType = value.ValueType
+	case TupleValue:
+		valueType = value.ValueType
+	case CustomValue:
+		valueType = value.ValueType
+	}
+
+	if valueType == nil {
+		return fmt.Errorf("%s without a ValueType", typeName(value))
+	}
+	if requirer, ok := valueType.(funcRequirer); ok && requirer.missingFunc() {
+		return fmt.Errorf("nil ValidationFunc: %s", valueName(value))
+	}
+
+	return nil
+}
+
+// stringValue reports whether a right can match a string literal.
+func stringValue(right Right) bool {
+	var valueType any = right
+	if value, ok := right.(LiteralValue); ok {
+		valueType = value.ValueType
+	}
+
+	switch valueType.(type) {
+	case IntegerValue, Int64Value, Uint64Value, FloatValue, DecimalValue, BooleanValue:
+		return false
+	default:
+		return true
+	}
+}
+
+// valueName names a value by its value type, e.g. LiteralValue{StringValue}.
+func valueName(value any) string {
+	switch value := value.(type) {
+	case LiteralValue:
+		return fmt.Sprintf("%s{%s}", typeName(value), typeName(value.ValueType))
+	case TupleValue:
+		return fmt.Sprintf("%s{%s}", typeName(value), typeName(value.ValueType))
+	default:
+		return typeName(value)
+	}
+}
+
+func typeName(value any) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", value), "filtersql.")
+}
+
+func qualifiedName(column Column) string {
+	return strings.TrimPrefix(column.Qualifier+"."+column.Name, ".")
+}